@@ -0,0 +1,307 @@
+package aleo_utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// minimal arena size, enough for a key, an address and a signature
+const minArenaSize = 1024
+
+// maximal number of parameters of a guest function
+const maxCallParams = 16
+
+var (
+	errArenaOverflow = errors.New("session arena overflow")
+	errNullResult    = errors.New("guest function returned null")
+)
+
+// guestArena is a grow-only scratch buffer in the guest memory, which is used to pass arguments to guest functions.
+// The arena is allocated once and reused by every call of a session, so passing arguments doesn't need
+// allocating and deallocating guest memory.
+type guestArena struct {
+	ptr  uint32
+	size uint32
+	// offset of the free part of the arena for the current call
+	used uint32
+}
+
+// resetArena prepares the arena for a new call, making sure it fits the total size of the call arguments.
+// The arena is allocated on the first call even if the arguments are empty, so argument pointers are never null.
+func (s *aleoWrapperSession) resetArena(total int) error {
+	s.arena.used = 0
+
+	if s.arena.size != 0 && uint64(total) <= uint64(s.arena.size) {
+		return nil
+	}
+
+	newSize := max(uint64(total), 2*uint64(s.arena.size), minArenaSize)
+	if newSize > uint64(^uint32(0)) {
+		return errArenaOverflow
+	}
+
+	ptr, err := s.call(s.allocate, newSize)
+	if err != nil {
+		return err
+	}
+	if ptr == 0 {
+		return errors.New("failed to allocate session arena")
+	}
+
+	if s.arena.size != 0 {
+		if _, err := s.call(s.deallocate, uint64(s.arena.ptr), uint64(s.arena.size)); err != nil {
+			return err
+		}
+	}
+
+	s.arena.ptr = uint32(ptr)
+	s.arena.size = uint32(newSize)
+
+	return nil
+}
+
+// putBytes writes an argument to the arena and returns its guest pointer.
+func (s *aleoWrapperSession) putBytes(buf []byte) (uint64, error) {
+	if uint64(s.arena.used)+uint64(len(buf)) > uint64(s.arena.size) {
+		return 0, errArenaOverflow
+	}
+
+	ptr := s.arena.ptr + s.arena.used
+	if !s.mod.Memory().Write(ptr, buf) {
+		return 0, errors.New("failed to write to session arena")
+	}
+	s.arena.used += uint32(len(buf))
+
+	return uint64(ptr), nil
+}
+
+// putString is like putBytes, but avoids converting the string to a byte slice.
+func (s *aleoWrapperSession) putString(str string) (uint64, error) {
+	if uint64(s.arena.used)+uint64(len(str)) > uint64(s.arena.size) {
+		return 0, errArenaOverflow
+	}
+
+	ptr := s.arena.ptr + s.arena.used
+	if !s.mod.Memory().WriteString(ptr, str) {
+		return 0, errors.New("failed to write to session arena")
+	}
+	s.arena.used += uint32(len(str))
+
+	return uint64(ptr), nil
+}
+
+// packedSize returns the size of buffers packed with putPacked.
+func packedSize(bufs [][]byte) int {
+	size := 0
+	for _, buf := range bufs {
+		size += 4 + len(buf)
+	}
+
+	return size
+}
+
+// packStrings packs strings the same way as putPacked, so a list of strings can be passed to callWithStrings
+// as a single argument.
+func packStrings(strs []string) string {
+	size := 0
+	for _, str := range strs {
+		size += 4 + len(str)
+	}
+
+	buf := make([]byte, 0, size)
+	for _, str := range strs {
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(str)))
+		buf = append(buf, str...)
+	}
+
+	return string(buf)
+}
+
+// putPacked writes multiple buffers to the arena back to back, each buffer is prefixed with its length
+// as 4 little-endian bytes. Returns the guest pointer to the packed buffers.
+func (s *aleoWrapperSession) putPacked(bufs [][]byte) (uint64, error) {
+	start := uint64(s.arena.ptr + s.arena.used)
+
+	var lenBuf [4]byte
+	for _, buf := range bufs {
+		binary.LittleEndian.PutUint32(lenBuf[:], uint32(len(buf)))
+		if _, err := s.putBytes(lenBuf[:]); err != nil {
+			return 0, err
+		}
+		if _, err := s.putBytes(buf); err != nil {
+			return 0, err
+		}
+	}
+
+	return start, nil
+}
+
+// call calls a guest function using the session stack to avoid allocating parameter and result slices.
+// Returns the first result, or 0 if the function has no results.
+func (s *aleoWrapperSession) call(fn api.Function, params ...uint64) (uint64, error) {
+	stack := s.stack[:max(len(params), 1)]
+	copy(stack, params)
+
+	if err := fn.CallWithStack(s.ctx, stack); err != nil {
+		return 0, err
+	}
+
+	return stack[0], nil
+}
+
+// callStrings writes string arguments to the arena and calls a guest function with a pointer and a length of every
+// argument followed by the extra parameters. Returns errNullResult if the function returns 0.
+func (s *aleoWrapperSession) callStrings(fn api.Function, args []string, extra ...uint64) (uint64, error) {
+	if 2*len(args)+len(extra) > maxCallParams {
+		return 0, errors.New("too many guest function arguments")
+	}
+
+	total := 0
+	for _, arg := range args {
+		total += len(arg)
+	}
+	if err := s.resetArena(total); err != nil {
+		return 0, err
+	}
+
+	var params [maxCallParams]uint64
+	for i, arg := range args {
+		ptr, err := s.putString(arg)
+		if err != nil {
+			return 0, err
+		}
+		params[2*i] = ptr
+		params[2*i+1] = uint64(len(arg))
+	}
+	n := 2*len(args) + copy(params[2*len(args):], extra)
+
+	result, err := s.call(fn, params[:n]...)
+	if err != nil {
+		return 0, err
+	}
+	if result == 0 {
+		return 0, errNullResult
+	}
+
+	return result, nil
+}
+
+// callWithStrings is like callStrings, but reads the string, which the function returns as a pointer and a length.
+func (s *aleoWrapperSession) callWithStrings(fn api.Function, args []string, extra ...uint64) (string, error) {
+	result, err := s.callStrings(fn, args, extra...)
+	if err != nil {
+		return "", err
+	}
+
+	str, ok := s.readResultString(unpackResult(result))
+	if !ok {
+		return "", errors.New("failed to read guest function result")
+	}
+
+	return str, nil
+}
+
+// unpackResult splits a guest result into a pointer and a length. The higher 32 bits of a result are the buffer
+// length, the lower 32 bits are the pointer to the buffer.
+func unpackResult(result uint64) (ptr uint32, length uint32) {
+	return uint32(result), uint32(result >> 32)
+}
+
+// readResult copies a buffer returned by the guest and deallocates it.
+func (s *aleoWrapperSession) readResult(ptr uint32, length uint32) ([]byte, bool) {
+	buf, ok := s.mod.Memory().Read(ptr, length)
+	if !ok {
+		return nil, false
+	}
+
+	// since memory read returns a slice of wasm memory buffer, it needs to be copied
+	// to avoid our returned slice being wiped when wasm memory is wiped
+	result := make([]byte, len(buf))
+	copy(result, buf)
+
+	s.call(s.deallocate, uint64(ptr), uint64(length))
+
+	return result, true
+}
+
+// readResultString is like readResult, but returns a string.
+func (s *aleoWrapperSession) readResultString(ptr uint32, length uint32) (string, bool) {
+	buf, ok := s.mod.Memory().Read(ptr, length)
+	if !ok {
+		return "", false
+	}
+
+	// explicit copy is not needed since we create a string, which copies the slice instead of referencing it
+	result := string(buf)
+
+	s.call(s.deallocate, uint64(ptr), uint64(length))
+
+	return result, true
+}
+
+// readResultWithoutNewlines is like readResult, but drops new lines from the buffer while copying it.
+func (s *aleoWrapperSession) readResultWithoutNewlines(ptr uint32, length uint32) ([]byte, bool) {
+	buf, ok := s.mod.Memory().Read(ptr, length)
+	if !ok {
+		return nil, false
+	}
+
+	result := copyWithoutNewlines(buf)
+
+	s.call(s.deallocate, uint64(ptr), uint64(length))
+
+	return result, true
+}
+
+// copyWithoutNewlines returns a copy of the buffer with new lines dropped.
+func copyWithoutNewlines(buf []byte) []byte {
+	result := make([]byte, 0, len(buf)-bytes.Count(buf, []byte{'\n'}))
+	for len(buf) > 0 {
+		i := bytes.IndexByte(buf, '\n')
+		if i < 0 {
+			result = append(result, buf...)
+			break
+		}
+		result = append(result, buf[:i]...)
+		buf = buf[i+1:]
+	}
+
+	return result
+}
+
+// unpackFields splits a buffer returned by the guest, which packs multiple buffers each prefixed with
+// its length as 4 little-endian bytes, and checks the number of buffers. The returned fields reference
+// the original buffer.
+func unpackFields(buf []byte, count int) ([][]byte, bool) {
+	fields, ok := unpackAllFields(buf, count)
+	if !ok || len(fields) != count {
+		return nil, false
+	}
+
+	return fields, true
+}
+
+// unpackAllFields is like unpackFields, but accepts any number of buffers. The capacity is a hint
+// for the expected number of buffers.
+func unpackAllFields(buf []byte, capacity int) ([][]byte, bool) {
+	fields := make([][]byte, 0, capacity)
+	for len(buf) > 0 {
+		if len(buf) < 4 {
+			return nil, false
+		}
+
+		fieldLen := binary.LittleEndian.Uint32(buf)
+		buf = buf[4:]
+		if uint64(fieldLen) > uint64(len(buf)) {
+			return nil, false
+		}
+
+		fields = append(fields, buf[:fieldLen])
+		buf = buf[fieldLen:]
+	}
+
+	return fields, true
+}
@@ -0,0 +1,219 @@
+package aleo_utils
+
+import (
+	"errors"
+	"log"
+
+	"github.com/zkportal/aleo-utils-go/codec"
+)
+
+// status of a batch item result returned by the module
+const (
+	batchItemFailed = 0
+	batchItemOK     = 1
+)
+
+// HashResult is a result of hashing a single message in a batch.
+type HashResult struct {
+	// Hash is the same as the result of HashMessage
+	Hash []byte
+	Err  error
+}
+
+// SignatureResult is a result of signing a single message in a batch.
+type SignatureResult struct {
+	// Signature is the same as the result of Sign
+	Signature string
+	Err       error
+}
+
+// HashMessageBatch hashes multiple messages in one call, the same way as HashMessage. A failure to hash
+// a message is reported in its result, err is only returned if the whole batch failed.
+//
+// If the module doesn't support FeatureBatch, the messages are hashed using separate calls.
+func (s *aleoWrapperSession) HashMessageBatch(messages [][]byte) (results []HashResult, err error) {
+	if s.mod == nil || s.mod.IsClosed() {
+		return nil, ErrNoModule
+	}
+
+	if s.hashMessageBatch == nil {
+		results = make([]HashResult, len(messages))
+		for i, message := range messages {
+			results[i].Hash, results[i].Err = s.HashMessage(message)
+		}
+		return results, nil
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// find out exactly what the error was and set err
+			switch x := r.(type) {
+			case string:
+				err = errors.New(x)
+			case error:
+				err = x
+			default:
+				err = errors.New("unknown panic")
+			}
+			results = nil
+		}
+	}()
+
+	messagesLen := packedSize(messages)
+
+	if err := s.resetArena(messagesLen); err != nil {
+		log.Println("arena error:", err)
+		return nil, errors.New("failed to allocate memory for messages")
+	}
+
+	// write messages to wasm memory
+	messagesPtr, err := s.putPacked(messages)
+	if err != nil {
+		return nil, errors.New("failed to write messages to memory for hashing")
+	}
+
+	batchResult, err := s.call(s.hashMessageBatch, messagesPtr, uint64(messagesLen))
+	if err != nil {
+		log.Println("hash message batch error:", err)
+		return nil, errors.New("failed to hash messages")
+	}
+	if batchResult == 0 {
+		return nil, errors.New("invalid message batch")
+	}
+
+	items, err := s.readBatchResult(batchResult, len(messages))
+	if err != nil {
+		return nil, err
+	}
+
+	results = make([]HashResult, len(messages))
+	for i, item := range items {
+		if item == nil {
+			results[i].Err = errors.New("invalid message")
+			continue
+		}
+		results[i].Hash = item
+	}
+
+	return results, nil
+}
+
+// SignBatch signs multiple messages with the same key in one call, the same way as Sign, using the signing options
+// of the wrapper. The key is parsed only once. A failure to sign a message is reported in its result, err is only
+// returned if the whole batch failed.
+//
+// If the module doesn't support FeatureBatch, the messages are signed using separate calls.
+func (s *aleoWrapperSession) SignBatch(key string, messages [][]byte) (results []SignatureResult, err error) {
+	if s.mod == nil || s.mod.IsClosed() {
+		return nil, ErrNoModule
+	}
+
+	if len(key) != PRIVATE_KEY_SIZE {
+		return nil, errors.New("invalid private key size")
+	}
+	if err := codec.ValidatePrivateKey(key); err != nil {
+		return nil, err
+	}
+
+	if s.signBatch == nil {
+		results = make([]SignatureResult, len(messages))
+		for i, message := range messages {
+			results[i].Signature, results[i].Err = s.Sign(key, message)
+		}
+		return results, nil
+	}
+
+	if s.signOptions.DeterministicNonce && !s.deterministicNonce {
+		return nil, ErrNotSupported
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// find out exactly what the error was and set err
+			switch x := r.(type) {
+			case string:
+				err = errors.New(x)
+			case error:
+				err = x
+			default:
+				err = errors.New("unknown panic")
+			}
+			results = nil
+		}
+	}()
+
+	messagesLen := packedSize(messages)
+
+	if err := s.resetArena(PRIVATE_KEY_SIZE + messagesLen); err != nil {
+		log.Println("arena error:", err)
+		return nil, errors.New("failed to allocate memory for signing")
+	}
+
+	// write private key to wasm memory
+	privateKeyPtr, err := s.putString(key)
+	if err != nil {
+		return nil, errors.New("failed to write private key to memory for signing")
+	}
+
+	// write messages to wasm memory
+	messagesPtr, err := s.putPacked(messages)
+	if err != nil {
+		return nil, errors.New("failed to write messages to memory for signing")
+	}
+
+	batchResult, err := s.call(s.signBatch, privateKeyPtr, PRIVATE_KEY_SIZE, messagesPtr, uint64(messagesLen), s.signOptions.flags())
+	if err != nil {
+		log.Println("sign batch error:", err)
+		return nil, errors.New("failed to sign messages")
+	}
+	if batchResult == 0 {
+		return nil, errors.New("internal error when signing messages")
+	}
+
+	items, err := s.readBatchResult(batchResult, len(messages))
+	if err != nil {
+		return nil, err
+	}
+
+	results = make([]SignatureResult, len(messages))
+	for i, item := range items {
+		if item == nil {
+			results[i].Err = errors.New("failed to sign message")
+			continue
+		}
+		results[i].Signature = string(item)
+	}
+
+	return results, nil
+}
+
+// readBatchResult reads batch item results returned by the module. A failed item is returned as nil.
+func (s *aleoWrapperSession) readBatchResult(batchResult uint64, count int) ([][]byte, error) {
+	buf, ok := s.readResult(unpackResult(batchResult))
+	if !ok {
+		return nil, errors.New("failed to read batch result")
+	}
+
+	fields, ok := unpackFields(buf, count)
+	if !ok {
+		return nil, errors.New("unexpected batch result")
+	}
+
+	items := make([][]byte, count)
+	for i, field := range fields {
+		if len(field) == 0 {
+			return nil, errors.New("unexpected batch result")
+		}
+
+		switch field[0] {
+		case batchItemOK:
+			items[i] = field[1:]
+		case batchItemFailed:
+			items[i] = nil
+		default:
+			return nil, errors.New("unexpected batch item status")
+		}
+	}
+
+	return items, nil
+}
@@ -0,0 +1,135 @@
+package aleo_utils
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/tetratelabs/wazero"
+)
+
+// Range of the module ABI versions supported by the wrapper. Version 0 is a module without the ABI handshake.
+const (
+	MIN_ABI_VERSION = 0
+	MAX_ABI_VERSION = 1
+)
+
+// Optional module features, which can be checked with Capabilities.HasFeature
+const (
+	FeatureVerify        = "verify"
+	FeatureFormatAndHash = "format_and_hash"
+	FeatureBatch         = "batch"
+	FeatureKeyHandles    = "key_handles"
+	FeatureSignOptions   = "sign_options"
+	// FeatureDeterministicNonce is support of SignOptions.DeterministicNonce
+	FeatureDeterministicNonce  = "deterministic_nonce"
+	FeatureRecords             = "records"
+	FeatureRecordEncryption    = "record_encryption"
+	FeatureRecordSerialNumbers = "record_serial_numbers"
+	FeatureTransitions         = "transitions"
+	FeaturePrograms            = "programs"
+	FeatureRequests            = "requests"
+	FeatureFeeAuthorizations   = "fee_authorizations"
+	FeatureTransactions        = "transactions"
+	FeatureTransactionChecks   = "transaction_checks"
+)
+
+// featureExports maps optional features to the module functions implementing them, used to describe
+// modules without the ABI handshake
+var featureExports = map[string][]string{
+	FeatureVerify:              {"verify"},
+	FeatureFormatAndHash:       {"format_and_hash", "format_hash_sign"},
+	FeatureBatch:               {"hash_message_batch", "sign_batch"},
+	FeatureKeyHandles:          {"load_key", "release_key", "release_all_keys", "sign_with_key"},
+	FeatureSignOptions:         {"sign_with_options"},
+	FeatureRecords:             {"decrypt_record", "is_owner"},
+	FeatureRecordEncryption:    {"encrypt_record"},
+	FeatureRecordSerialNumbers: {"record_commitment", "record_serial_number", "record_tag"},
+	FeatureTransitions:         {"transition_view_key", "decrypt_transition_ciphertext"},
+	FeaturePrograms:            {"program_address", "mapping_key_id", "plaintext_to_fields"},
+	FeatureRequests:            {"sign_request"},
+	FeatureFeeAuthorizations:   {"authorize_fee_public"},
+	FeatureTransactions:        {"parse_transaction"},
+	FeatureTransactionChecks:   {"check_transaction"},
+}
+
+var ErrIncompatibleModule = errors.New("wrapper module is incompatible")
+
+// Capabilities describes what the WASM module supports. Use it to feature-detect before calling
+// optional session functions.
+type Capabilities struct {
+	ABIVersion     uint32
+	Networks       []string
+	HashAlgorithms []string
+	SigningModes   []string
+	Features       []string
+}
+
+// HasFeature reports whether the module supports an optional feature, e.g. FeatureVerify.
+func (c Capabilities) HasFeature(feature string) bool {
+	return slices.Contains(c.Features, feature)
+}
+
+// parseCapabilities parses the capabilities reported by the module as lines of `key=value1,value2`.
+// Unknown keys are ignored.
+func parseCapabilities(raw string) (Capabilities, error) {
+	var caps Capabilities
+
+	for _, line := range strings.Split(raw, "\n") {
+		if line == "" {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return Capabilities{}, fmt.Errorf("%w: invalid capability %q", ErrIncompatibleModule, line)
+		}
+
+		var values []string
+		if value != "" {
+			values = strings.Split(value, ",")
+		}
+
+		switch key {
+		case "networks":
+			caps.Networks = values
+		case "hash":
+			caps.HashAlgorithms = values
+		case "signing":
+			caps.SigningModes = values
+		case "features":
+			caps.Features = values
+		}
+	}
+
+	return caps, nil
+}
+
+// legacyCapabilities describes a module without the ABI handshake using its exports.
+func legacyCapabilities(cmod wazero.CompiledModule) Capabilities {
+	caps := Capabilities{
+		ABIVersion:     0,
+		Networks:       []string{"testnet"},
+		HashAlgorithms: []string{"poseidon8"},
+		SigningModes:   []string{"schnorr"},
+	}
+
+	exported := cmod.ExportedFunctions()
+	for feature, exports := range featureExports {
+		supported := true
+		for _, name := range exports {
+			if _, ok := exported[name]; !ok {
+				supported = false
+				break
+			}
+		}
+
+		if supported {
+			caps.Features = append(caps.Features, feature)
+		}
+	}
+	slices.Sort(caps.Features)
+
+	return caps
+}
@@ -71,7 +71,13 @@ func main() {
 		log.Fatalln(err)
 	}
 
-	signature, err := s.Sign("APrivateKey1zkpEem71u7U75h5VodKNgyR37aGJBj4ZTgagCHm3qsuz5PU", []byte(hashedReport))
+	// sign the little-endian bytes of the report hash, so the signature covers the whole u128
+	hashedReportBytes, err := s.HashMessage(formattedReport)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	signature, err := s.Sign("APrivateKey1zkpEem71u7U75h5VodKNgyR37aGJBj4ZTgagCHm3qsuz5PU", hashedReportBytes)
 	if err != nil {
 		log.Fatalln(err)
 	}
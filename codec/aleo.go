@@ -0,0 +1,218 @@
+// Package codec encodes, decodes and validates Aleo identifiers without the WASM module: bech32m addresses,
+// signatures and IDs, and base58 private and view keys.
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Human-readable parts of bech32m Aleo identifiers
+const (
+	AddressPrefix       = "aleo"
+	SignaturePrefix     = "sign"
+	TransactionIDPrefix = "at"
+	TransitionIDPrefix  = "au"
+	BlockHashPrefix     = "ab"
+	RecordPrefix        = "record"
+	CiphertextPrefix    = "ciphertext"
+)
+
+// Sizes of the decoded payloads
+const (
+	// FieldSize is the size of a field element or a scalar
+	FieldSize     = 32
+	AddressSize   = FieldSize
+	SignatureSize = 4 * FieldSize
+	// PrivateKeySize is the size of the private key seed
+	PrivateKeySize = FieldSize
+	// ViewKeySize is the size of the view key scalar
+	ViewKeySize = FieldSize
+)
+
+// String prefixes of base58 Aleo secrets
+const (
+	PrivateKeyPrefix = "APrivateKey1"
+	ViewKeyPrefix    = "AViewKey1"
+)
+
+// byte prefixes of base58 Aleo secrets, which make the encoded strings start with the string prefixes
+var (
+	privateKeyBytesPrefix = []byte{127, 134, 189, 116, 210, 221, 210, 137, 145, 18, 253}
+	viewKeyBytesPrefix    = []byte{14, 138, 223, 204, 247, 224, 122}
+)
+
+var (
+	ErrInvalidAddress    = errors.New("invalid address")
+	ErrInvalidPrivateKey = errors.New("invalid private key")
+	ErrInvalidViewKey    = errors.New("invalid view key")
+	ErrInvalidRecord     = errors.New("invalid record ciphertext")
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+)
+
+// DecodeBech32m decodes a bech32m identifier, checking its human-readable part and payload size.
+func DecodeBech32m(str string, hrp string, size int) ([]byte, error) {
+	decodedHrp, data, err := Bech32mDecode(str)
+	if err != nil {
+		return nil, err
+	}
+
+	if decodedHrp != hrp {
+		return nil, fmt.Errorf("unexpected prefix %q, expected %q", decodedHrp, hrp)
+	}
+
+	if len(data) != size {
+		return nil, fmt.Errorf("unexpected payload size %d, expected %d", len(data), size)
+	}
+
+	return data, nil
+}
+
+// DecodeAddress decodes an `aleo1...` address into the little-endian x-coordinate of the address point.
+func DecodeAddress(address string) ([]byte, error) {
+	data, err := DecodeBech32m(address, AddressPrefix, AddressSize)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAddress, err)
+	}
+
+	return data, nil
+}
+
+// EncodeAddress encodes the little-endian x-coordinate of an address point as an `aleo1...` address.
+func EncodeAddress(x []byte) (string, error) {
+	if len(x) != AddressSize {
+		return "", fmt.Errorf("%w: unexpected size %d", ErrInvalidAddress, len(x))
+	}
+
+	return Bech32mEncode(AddressPrefix, x), nil
+}
+
+// maximal size of a decoded base58 secret with its byte prefix
+const maxBase58SecretSize = 64
+
+// decodeBase58 decodes a base58 secret into buf, checking its prefix and payload size.
+func decodeBase58(buf []byte, str string, prefix string, bytesPrefix []byte, size int) ([]byte, error) {
+	if !strings.HasPrefix(str, prefix) {
+		return nil, fmt.Errorf("expected prefix %q", prefix)
+	}
+
+	data, err := base58DecodeTo(buf, str)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) != len(bytesPrefix)+size || !bytes.HasPrefix(data, bytesPrefix) {
+		return nil, errors.New("unexpected payload")
+	}
+
+	return data[len(bytesPrefix):], nil
+}
+
+func encodeBase58(bytesPrefix []byte, payload []byte) string {
+	data := make([]byte, 0, len(bytesPrefix)+len(payload))
+	data = append(data, bytesPrefix...)
+	data = append(data, payload...)
+
+	return Base58Encode(data)
+}
+
+// DecodePrivateKey decodes an `APrivateKey1...` private key into its little-endian seed.
+func DecodePrivateKey(key string) ([]byte, error) {
+	seed, err := decodeBase58(make([]byte, 0, maxBase58SecretSize), key, PrivateKeyPrefix, privateKeyBytesPrefix, PrivateKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPrivateKey, err)
+	}
+
+	return seed, nil
+}
+
+// EncodePrivateKey encodes a little-endian private key seed as an `APrivateKey1...` private key.
+func EncodePrivateKey(seed []byte) (string, error) {
+	if len(seed) != PrivateKeySize {
+		return "", fmt.Errorf("%w: unexpected size %d", ErrInvalidPrivateKey, len(seed))
+	}
+
+	return encodeBase58(privateKeyBytesPrefix, seed), nil
+}
+
+// DecodeViewKey decodes an `AViewKey1...` view key into its little-endian scalar.
+func DecodeViewKey(key string) ([]byte, error) {
+	scalar, err := decodeBase58(make([]byte, 0, maxBase58SecretSize), key, ViewKeyPrefix, viewKeyBytesPrefix, ViewKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidViewKey, err)
+	}
+
+	return scalar, nil
+}
+
+// EncodeViewKey encodes a little-endian view key scalar as an `AViewKey1...` view key.
+func EncodeViewKey(scalar []byte) (string, error) {
+	if len(scalar) != ViewKeySize {
+		return "", fmt.Errorf("%w: unexpected size %d", ErrInvalidViewKey, len(scalar))
+	}
+
+	return encodeBase58(viewKeyBytesPrefix, scalar), nil
+}
+
+// ValidateAddress checks that the string is a well-formed `aleo1...` address with a valid checksum.
+func ValidateAddress(address string) error {
+	_, err := DecodeAddress(address)
+	return err
+}
+
+// ValidatePrivateKey checks that the string is a well-formed `APrivateKey1...` private key.
+// It doesn't allocate for valid keys, and wipes the decoded seed.
+func ValidatePrivateKey(key string) error {
+	var buf [maxBase58SecretSize]byte
+	defer clear(buf[:])
+
+	if _, err := decodeBase58(buf[:0], key, PrivateKeyPrefix, privateKeyBytesPrefix, PrivateKeySize); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPrivateKey, err)
+	}
+
+	return nil
+}
+
+// ValidateViewKey checks that the string is a well-formed `AViewKey1...` view key.
+func ValidateViewKey(key string) error {
+	var buf [maxBase58SecretSize]byte
+	defer clear(buf[:])
+
+	if _, err := decodeBase58(buf[:0], key, ViewKeyPrefix, viewKeyBytesPrefix, ViewKeySize); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidViewKey, err)
+	}
+
+	return nil
+}
+
+// ValidateRecordCiphertext checks that the string is a well-formed `record1...` record ciphertext with a valid
+// checksum. The record content is not checked.
+func ValidateRecordCiphertext(record string) error {
+	hrp, _, err := Bech32mDecode(record)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidRecord, err)
+	}
+
+	if hrp != RecordPrefix {
+		return fmt.Errorf("%w: unexpected prefix %q", ErrInvalidRecord, hrp)
+	}
+
+	return nil
+}
+
+// ValidateCiphertext checks that the string is a well-formed `ciphertext1...` ciphertext of a private transition
+// input or output with a valid checksum.
+func ValidateCiphertext(ciphertext string) error {
+	hrp, _, err := Bech32mDecode(ciphertext)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCiphertext, err)
+	}
+
+	if hrp != CiphertextPrefix {
+		return fmt.Errorf("%w: unexpected prefix %q", ErrInvalidCiphertext, hrp)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package codec
+
+import (
+	"fmt"
+)
+
+// base58 with the Bitcoin alphabet, used by Aleo for private keys, view keys and other secrets
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var base58AlphabetRev = func() (rev [128]int8) {
+	for i := range rev {
+		rev[i] = -1
+	}
+	for i, c := range base58Alphabet {
+		rev[c] = int8(i)
+	}
+	return rev
+}()
+
+// Base58Encode encodes the data using the Bitcoin base58 alphabet. Leading zero bytes are encoded as '1'.
+func Base58Encode(data []byte) string {
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+
+	// log(256) / log(58) < 1.37
+	digits := make([]byte, 0, len(data)*137/100+1)
+	for _, b := range data[zeros:] {
+		carry := int(b)
+		for i := range digits {
+			carry += int(digits[i]) << 8
+			digits[i] = byte(carry % 58)
+			carry /= 58
+		}
+		for carry > 0 {
+			digits = append(digits, byte(carry%58))
+			carry /= 58
+		}
+	}
+
+	result := make([]byte, zeros+len(digits))
+	for i := 0; i < zeros; i++ {
+		result[i] = base58Alphabet[0]
+	}
+	for i, d := range digits {
+		result[len(result)-1-i] = base58Alphabet[d]
+	}
+
+	return string(result)
+}
+
+// Base58Decode decodes a string encoded with Base58Encode.
+func Base58Decode(str string) ([]byte, error) {
+	// log(58) / log(256) < 0.74
+	return base58DecodeTo(make([]byte, 0, len(str)*74/100+1), str)
+}
+
+// base58DecodeTo is like Base58Decode, but decodes into buf, so that decoding doesn't allocate if buf is large enough.
+func base58DecodeTo(buf []byte, str string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(str) && str[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+
+	// the number is accumulated in little-endian order
+	result := buf[:0]
+	for i := zeros; i < len(str); i++ {
+		c := str[i]
+		if c >= 128 || base58AlphabetRev[c] < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", c)
+		}
+
+		carry := int(base58AlphabetRev[c])
+		for j := range result {
+			carry += int(result[j]) * 58
+			result[j] = byte(carry)
+			carry >>= 8
+		}
+		for carry > 0 {
+			result = append(result, byte(carry))
+			carry >>= 8
+		}
+	}
+
+	for i := 0; i < zeros; i++ {
+		result = append(result, 0)
+	}
+
+	// convert to big-endian order
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+
+	return result, nil
+}
@@ -0,0 +1,155 @@
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// bech32m implementation (BIP-350), used by Aleo for addresses, signatures, IDs and other identifiers
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// checksum constant of bech32m, bech32 uses 1
+const bech32mConst = 0x2bc830a3
+
+const bech32ChecksumSize = 6
+
+var bech32CharsetRev = func() (rev [128]int8) {
+	for i := range rev {
+		rev[i] = -1
+	}
+	for i, c := range bech32Charset {
+		rev[c] = int8(i)
+	}
+	return rev
+}()
+
+var ErrChecksum = errors.New("invalid checksum")
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+
+	return chk
+}
+
+func bech32HrpExpand(hrp string) []byte {
+	result := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		result = append(result, hrp[i]>>5)
+	}
+	result = append(result, 0)
+	for i := 0; i < len(hrp); i++ {
+		result = append(result, hrp[i]&31)
+	}
+
+	return result
+}
+
+func bech32CreateChecksum(hrp string, data []byte) []byte {
+	values := append(bech32HrpExpand(hrp), data...)
+	values = append(values, make([]byte, bech32ChecksumSize)...)
+	mod := bech32Polymod(values) ^ bech32mConst
+
+	checksum := make([]byte, bech32ChecksumSize)
+	for i := range checksum {
+		checksum[i] = byte(mod>>(5*(5-i))) & 31
+	}
+
+	return checksum
+}
+
+// convertBits regroups bits of the data from fromBits-sized to toBits-sized groups. When decoding (pad = false),
+// the leftover bits must be zero padding.
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	acc := uint32(0)
+	bits := uint(0)
+	maxV := uint32(1)<<toBits - 1
+
+	result := make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+	for _, v := range data {
+		if uint32(v)>>fromBits != 0 {
+			return nil, errors.New("invalid data range")
+		}
+		acc = acc<<fromBits | uint32(v)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			result = append(result, byte(acc>>bits&maxV))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			result = append(result, byte(acc<<(toBits-bits)&maxV))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxV != 0 {
+		return nil, errors.New("invalid padding")
+	}
+
+	return result, nil
+}
+
+// Bech32mEncode encodes the data bytes with the human-readable part, e.g. "aleo" for addresses.
+func Bech32mEncode(hrp string, data []byte) string {
+	// converting 8 to 5 bits with padding never fails
+	data5, _ := convertBits(data, 8, 5, true)
+	checksum := bech32CreateChecksum(hrp, data5)
+
+	var b strings.Builder
+	b.Grow(len(hrp) + 1 + len(data5) + len(checksum))
+	b.WriteString(hrp)
+	b.WriteByte('1')
+	for _, v := range append(data5, checksum...) {
+		b.WriteByte(bech32Charset[v])
+	}
+
+	return b.String()
+}
+
+// Bech32mDecode decodes a bech32m string, returning the human-readable part and the data bytes.
+// Only lowercase strings are accepted, as Aleo identifiers are always lowercase.
+func Bech32mDecode(str string) (hrp string, data []byte, err error) {
+	sep := strings.LastIndexByte(str, '1')
+	if sep < 1 || sep+1+bech32ChecksumSize > len(str) {
+		return "", nil, errors.New("invalid bech32m separator position")
+	}
+
+	hrp = str[:sep]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 || (hrp[i] >= 'A' && hrp[i] <= 'Z') {
+			return "", nil, fmt.Errorf("invalid bech32m character %q", hrp[i])
+		}
+	}
+
+	data5 := make([]byte, 0, len(str)-sep-1)
+	for i := sep + 1; i < len(str); i++ {
+		c := str[i]
+		if c >= 128 || bech32CharsetRev[c] < 0 {
+			return "", nil, fmt.Errorf("invalid bech32m character %q", c)
+		}
+		data5 = append(data5, byte(bech32CharsetRev[c]))
+	}
+
+	if bech32Polymod(append(bech32HrpExpand(hrp), data5...)) != bech32mConst {
+		return "", nil, ErrChecksum
+	}
+
+	data, err = convertBits(data5[:len(data5)-bech32ChecksumSize], 5, 8, false)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return hrp, data, nil
+}
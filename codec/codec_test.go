@@ -0,0 +1,211 @@
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// the key and the address are the same as in cmd/sgx
+const (
+	testPrivateKey = "APrivateKey1zkpEem71u7U75h5VodKNgyR37aGJBj4ZTgagCHm3qsuz5PU"
+	testAddress    = "aleo1vz6e7yyv9anm7xpnl02nzwz5qdgvaypx428gpx5vdjnhgle64cpsld55le"
+)
+
+func TestBech32m(t *testing.T) {
+	// valid bech32m strings from BIP-350
+	tests := []struct {
+		str  string
+		hrp  string
+		data []byte
+	}{
+		{
+			str:  "a1lqfn3a",
+			hrp:  "a",
+			data: []byte{},
+		},
+		{
+			str:  "abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx",
+			hrp:  "abcdef",
+			data: []byte{0xff, 0xbb, 0xcd, 0xeb, 0x38, 0xbd, 0xab, 0x49, 0xca, 0x30, 0x7b, 0x9a, 0xc5, 0xa9, 0x28, 0x39, 0x8a, 0x41, 0x88, 0x20},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			hrp, data, err := Bech32mDecode(tt.str)
+			if err != nil {
+				t.Fatalf("Bech32mDecode() error = %v", err)
+			}
+			if hrp != tt.hrp || !bytes.Equal(data, tt.data) {
+				t.Errorf("Bech32mDecode() = %s, %x, want %s, %x", hrp, data, tt.hrp, tt.data)
+			}
+			if encoded := Bech32mEncode(hrp, data); encoded != tt.str {
+				t.Errorf("Bech32mEncode() = %s, want %s", encoded, tt.str)
+			}
+		})
+	}
+
+	invalid := []string{
+		"",
+		"1qzzfhee",
+		"a1lqfn3q",
+		"A1LQFN3A",
+		"abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryb",
+		"abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryi",
+	}
+	for _, str := range invalid {
+		if _, _, err := Bech32mDecode(str); err == nil {
+			t.Errorf("Bech32mDecode(%q) should fail", str)
+		}
+	}
+}
+
+func TestBase58(t *testing.T) {
+	tests := []struct {
+		data []byte
+		str  string
+	}{
+		{data: []byte{}, str: ""},
+		{data: []byte{0}, str: "1"},
+		{data: []byte{0, 0, 1}, str: "112"},
+		{data: []byte("hello world"), str: "StV1DL6CwTryKyV"},
+	}
+	for _, tt := range tests {
+		if str := Base58Encode(tt.data); str != tt.str {
+			t.Errorf("Base58Encode(%x) = %s, want %s", tt.data, str, tt.str)
+		}
+		data, err := Base58Decode(tt.str)
+		if err != nil {
+			t.Fatalf("Base58Decode(%s) error = %v", tt.str, err)
+		}
+		if !bytes.Equal(data, tt.data) {
+			t.Errorf("Base58Decode(%s) = %x, want %x", tt.str, data, tt.data)
+		}
+	}
+
+	if _, err := Base58Decode("0OIl"); err == nil {
+		t.Error("Base58Decode() should fail on characters outside of the alphabet")
+	}
+}
+
+func TestPrivateKey(t *testing.T) {
+	seed, err := DecodePrivateKey(testPrivateKey)
+	if err != nil {
+		t.Fatalf("DecodePrivateKey() error = %v", err)
+	}
+
+	key, err := EncodePrivateKey(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != testPrivateKey {
+		t.Errorf("EncodePrivateKey() = %s, want %s", key, testPrivateKey)
+	}
+
+	// every seed must be encoded with the private key prefix
+	for _, seed := range [][]byte{make([]byte, PrivateKeySize), bytes.Repeat([]byte{0xff}, PrivateKeySize)} {
+		key, err := EncodePrivateKey(seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ValidatePrivateKey(key); err != nil {
+			t.Errorf("ValidatePrivateKey(%s) error = %v", key, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		testPrivateKey[:len(testPrivateKey)-1],
+		testPrivateKey + "1",
+		"APrivateKey2" + testPrivateKey[12:],
+		testAddress,
+	}
+	for _, key := range invalid {
+		if err := ValidatePrivateKey(key); !errors.Is(err, ErrInvalidPrivateKey) {
+			t.Errorf("ValidatePrivateKey(%q) error = %v, want %v", key, err, ErrInvalidPrivateKey)
+		}
+	}
+}
+
+func TestViewKey(t *testing.T) {
+	for _, scalar := range [][]byte{make([]byte, ViewKeySize), bytes.Repeat([]byte{0xff}, ViewKeySize)} {
+		key, err := EncodeViewKey(scalar)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoded, err := DecodeViewKey(key)
+		if err != nil {
+			t.Fatalf("DecodeViewKey(%s) error = %v", key, err)
+		}
+		if !bytes.Equal(decoded, scalar) {
+			t.Errorf("DecodeViewKey(%s) = %x, want %x", key, decoded, scalar)
+		}
+	}
+
+	if err := ValidateViewKey(testPrivateKey); !errors.Is(err, ErrInvalidViewKey) {
+		t.Errorf("ValidateViewKey() error = %v, want %v", err, ErrInvalidViewKey)
+	}
+}
+
+func TestAddress(t *testing.T) {
+	x, err := DecodeAddress(testAddress)
+	if err != nil {
+		t.Fatalf("DecodeAddress() error = %v", err)
+	}
+
+	address, err := EncodeAddress(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address != testAddress {
+		t.Errorf("EncodeAddress() = %s, want %s", address, testAddress)
+	}
+
+	// a typo in a single character
+	typo := []byte(testAddress)
+	typo[10] = 'q'
+	if typo[10] == testAddress[10] {
+		typo[10] = 'p'
+	}
+
+	invalid := []string{
+		"",
+		string(typo),
+		testAddress[:len(testAddress)-1],
+		Bech32mEncode(SignaturePrefix, x),
+		Bech32mEncode(AddressPrefix, x[1:]),
+	}
+	for _, address := range invalid {
+		if err := ValidateAddress(address); !errors.Is(err, ErrInvalidAddress) {
+			t.Errorf("ValidateAddress(%q) error = %v, want %v", address, err, ErrInvalidAddress)
+		}
+	}
+}
+
+func TestValidateRecordCiphertext(t *testing.T) {
+	record := Bech32mEncode(RecordPrefix, bytes.Repeat([]byte{0xab}, 100))
+	if err := ValidateRecordCiphertext(record); err != nil {
+		t.Errorf("ValidateRecordCiphertext() error = %v", err)
+	}
+
+	ciphertext := Bech32mEncode(CiphertextPrefix, bytes.Repeat([]byte{0xab}, 100))
+	if err := ValidateCiphertext(ciphertext); err != nil {
+		t.Errorf("ValidateCiphertext() error = %v", err)
+	}
+	if err := ValidateCiphertext(record); !errors.Is(err, ErrInvalidCiphertext) {
+		t.Errorf("ValidateCiphertext() error = %v, want %v", err, ErrInvalidCiphertext)
+	}
+
+	invalid := []string{
+		"",
+		"{ owner: aleo1vz6e7yyv9anm7xpnl02nzwz5qdgvaypx428gpx5vdjnhgle64cpsld55le.private }",
+		record[:len(record)-1],
+		testAddress,
+	}
+	for _, record := range invalid {
+		if err := ValidateRecordCiphertext(record); !errors.Is(err, ErrInvalidRecord) {
+			t.Errorf("ValidateRecordCiphertext(%q) error = %v, want %v", record, err, ErrInvalidRecord)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package aleo_utils
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/zkportal/aleo-utils-go/codec"
+)
+
+// FormattedHash contains the artifacts of formatting and hashing a message.
+type FormattedHash struct {
+	// FormattedMessage is the same as the result of FormatMessage
+	FormattedMessage []byte
+	// HashString is the same as the result of HashMessageToString, e.g. "12345u128"
+	HashString string
+	// Hash is the same as the result of HashMessage
+	Hash []byte
+}
+
+// SignedMessage contains the artifacts of formatting, hashing and signing a message.
+type SignedMessage struct {
+	FormattedHash
+
+	// Signature is a signature of Hash, the same as the result of Sign
+	Signature string
+}
+
+// FormatAndHash formats a message and hashes it in one call, which is equivalent to calling FormatMessage,
+// HashMessageToString and HashMessage. The formatted message is hashed without parsing it again.
+//
+// If the module doesn't support FeatureFormatAndHash, the result is computed using separate calls.
+func (s *aleoWrapperSession) FormatAndHash(message []byte, targetChunks int) (result *FormattedHash, err error) {
+	if s.mod == nil || s.mod.IsClosed() {
+		return nil, ErrNoModule
+	}
+
+	if s.formatAndHash == nil {
+		return s.formatAndHashSeparately(message, targetChunks)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// find out exactly what the error was and set err
+			switch x := r.(type) {
+			case string:
+				err = errors.New(x)
+			case error:
+				err = x
+			default:
+				err = errors.New("unknown panic")
+			}
+			result = nil
+		}
+	}()
+
+	if err := checkFormatArguments(message, targetChunks); err != nil {
+		return nil, err
+	}
+
+	if err := s.resetArena(len(message)); err != nil {
+		log.Println("arena error:", err)
+		return nil, errors.New("failed to allocate memory for message")
+	}
+
+	// write message to wasm memory
+	messagePtr, err := s.putBytes(message)
+	if err != nil {
+		return nil, errors.New("failed to write message to memory for formatting")
+	}
+
+	packedResult, err := s.call(s.formatAndHash, messagePtr, uint64(len(message)), uint64(targetChunks))
+	if err != nil {
+		log.Println("format and hash error:", err)
+		return nil, errors.New("failed to format and hash message")
+	}
+	if packedResult == 0 {
+		return nil, errors.New("invalid message")
+	}
+
+	buf, ok := s.readResult(unpackResult(packedResult))
+	if !ok {
+		return nil, errors.New("failed to read formatted message and hash")
+	}
+
+	fields, ok := unpackFields(buf, 3)
+	if !ok {
+		return nil, errors.New("unexpected format and hash result")
+	}
+
+	return &FormattedHash{
+		FormattedMessage: copyWithoutNewlines(fields[0]),
+		HashString:       string(fields[1]),
+		Hash:             fields[2],
+	}, nil
+}
+
+// FormatHashSign formats a message, hashes it and signs the hash in one call, which is equivalent to calling
+// FormatMessage, HashMessageToString, HashMessage and Sign with the hash. It uses the signing options of the wrapper.
+//
+// If the module doesn't support FeatureFormatAndHash, the result is computed using separate calls.
+func (s *aleoWrapperSession) FormatHashSign(key string, message []byte, targetChunks int) (result *SignedMessage, err error) {
+	if s.mod == nil || s.mod.IsClosed() {
+		return nil, ErrNoModule
+	}
+
+	if s.formatHashSign == nil {
+		return s.formatHashSignSeparately(key, message, targetChunks)
+	}
+
+	if s.signOptions.DeterministicNonce && !s.deterministicNonce {
+		return nil, ErrNotSupported
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// find out exactly what the error was and set err
+			switch x := r.(type) {
+			case string:
+				err = errors.New(x)
+			case error:
+				err = x
+			default:
+				err = errors.New("unknown panic")
+			}
+			result = nil
+		}
+	}()
+
+	if len(key) != PRIVATE_KEY_SIZE {
+		return nil, errors.New("invalid private key size")
+	}
+	if err := codec.ValidatePrivateKey(key); err != nil {
+		return nil, err
+	}
+
+	if err := checkFormatArguments(message, targetChunks); err != nil {
+		return nil, err
+	}
+
+	if err := s.resetArena(PRIVATE_KEY_SIZE + len(message)); err != nil {
+		log.Println("arena error:", err)
+		return nil, errors.New("failed to allocate memory for signing")
+	}
+
+	// write private key to wasm memory
+	privateKeyPtr, err := s.putString(key)
+	if err != nil {
+		return nil, errors.New("failed to write private key to memory for signing")
+	}
+
+	// write message to wasm memory
+	messagePtr, err := s.putBytes(message)
+	if err != nil {
+		return nil, errors.New("failed to write message to memory for signing")
+	}
+
+	packedResult, err := s.call(s.formatHashSign, privateKeyPtr, PRIVATE_KEY_SIZE, messagePtr, uint64(len(message)), uint64(targetChunks), s.signOptions.flags())
+	if err != nil {
+		log.Println("format, hash and sign error:", err)
+		return nil, errors.New("failed to sign message")
+	}
+	if packedResult == 0 {
+		return nil, errors.New("internal error when signing message")
+	}
+
+	buf, ok := s.readResult(unpackResult(packedResult))
+	if !ok {
+		return nil, errors.New("failed to read signed message")
+	}
+
+	fields, ok := unpackFields(buf, 4)
+	if !ok {
+		return nil, errors.New("unexpected format, hash and sign result")
+	}
+
+	return &SignedMessage{
+		FormattedHash: FormattedHash{
+			FormattedMessage: copyWithoutNewlines(fields[0]),
+			HashString:       string(fields[1]),
+			Hash:             fields[2],
+		},
+		Signature: string(fields[3]),
+	}, nil
+}
+
+// formatAndHashSeparately is a fallback for modules without FeatureFormatAndHash
+func (s *aleoWrapperSession) formatAndHashSeparately(message []byte, targetChunks int) (*FormattedHash, error) {
+	formattedMessage, err := s.FormatMessage(message, targetChunks)
+	if err != nil {
+		return nil, err
+	}
+
+	hashString, err := s.HashMessageToString(formattedMessage)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := s.HashMessage(formattedMessage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FormattedHash{
+		FormattedMessage: formattedMessage,
+		HashString:       hashString,
+		Hash:             hash,
+	}, nil
+}
+
+// formatHashSignSeparately is a fallback for modules without FeatureFormatAndHash
+func (s *aleoWrapperSession) formatHashSignSeparately(key string, message []byte, targetChunks int) (*SignedMessage, error) {
+	formattedHash, err := s.formatAndHashSeparately(message, targetChunks)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := s.Sign(key, formattedHash.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignedMessage{
+		FormattedHash: *formattedHash,
+		Signature:     signature,
+	}, nil
+}
+
+func checkFormatArguments(message []byte, targetChunks int) error {
+	if targetChunks < 1 || targetChunks > MAX_FORMAT_MESSAGE_CHUNKS {
+		return errors.New("target number of chunks must be between 1 and 32")
+	}
+
+	if len(message) > targetChunks*MESSAGE_FORMAT_BLOCK_SIZE {
+		return fmt.Errorf("target formatted message length must be at most %d (%d chunks)", targetChunks*MESSAGE_FORMAT_BLOCK_SIZE, targetChunks)
+	}
+
+	return nil
+}
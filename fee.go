@@ -0,0 +1,156 @@
+package aleo_utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+
+	"github.com/zkportal/aleo-utils-go/codec"
+	"github.com/zkportal/aleo-utils-go/literal"
+)
+
+// ErrInvalidExecutionID is returned when an execution or deployment ID is not a field literal
+var ErrInvalidExecutionID = errors.New("invalid execution ID")
+
+// Authorization is a set of signed requests with the transitions they authorize. A prover executes it to create
+// a transaction or a fee without the private key.
+type Authorization struct {
+	// signed requests serialized as JSON, in the same form as returned by SignRequest
+	Requests []string
+	// transitions built from the requests, without proofs
+	Transitions []Transition
+
+	// authorization serialized as JSON
+	raw string
+}
+
+// String returns the authorization serialized as JSON, the form accepted by snarkOS and the Aleo SDK.
+func (a *Authorization) String() string {
+	return a.raw
+}
+
+// rawAuthorization is the JSON form of an authorization in snarkVM
+type rawAuthorization struct {
+	Requests    []json.RawMessage `json:"requests"`
+	Transitions []Transition      `json:"transitions"`
+}
+
+// decodeAuthorization parses an authorization JSON returned by the module
+func decodeAuthorization(authorization string) (*Authorization, error) {
+	raw := new(rawAuthorization)
+	if err := json.Unmarshal([]byte(authorization), raw); err != nil {
+		return nil, errors.New("invalid authorization returned by the module")
+	}
+
+	if len(raw.Requests) == 0 || len(raw.Requests) != len(raw.Transitions) {
+		return nil, errors.New("invalid authorization returned by the module")
+	}
+
+	requests := make([]string, 0, len(raw.Requests))
+	for _, request := range raw.Requests {
+		requests = append(requests, string(request))
+	}
+
+	return &Authorization{
+		Requests:    requests,
+		Transitions: raw.Transitions,
+		raw:         authorization,
+	}, nil
+}
+
+// SignFeePublic authorizes a `credits.aleo/fee_public` call, which pays the base fee and the priority fee in microcredits
+// for an execution or deployment from the public balance of the signer. The execution ID is a field literal, e.g.
+// `123field`. Returns the authorization with the signed request and the fee transition, so a prover can execute the fee
+// together with the execution.
+func (s *aleoWrapperSession) SignFeePublic(privateKey string, executionID string, baseFee uint64, priorityFee uint64) (authorization *Authorization, err error) {
+	if s.mod == nil || s.mod.IsClosed() {
+		return nil, ErrNoModule
+	}
+
+	inputs, err := feePublicInputs(executionID, baseFee, priorityFee)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.authorizeFee == nil {
+		return nil, ErrNotSupported
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// find out exactly what the error was and set err
+			switch x := r.(type) {
+			case string:
+				err = errors.New(x)
+			case error:
+				err = x
+			default:
+				err = errors.New("unknown panic")
+			}
+			authorization = nil
+		}
+	}()
+
+	if len(privateKey) != PRIVATE_KEY_SIZE {
+		return nil, errors.New("invalid private key size")
+	}
+	if err := codec.ValidatePrivateKey(privateKey); err != nil {
+		return nil, err
+	}
+
+	result, err := s.callWithStrings(s.authorizeFee, []string{privateKey, executionID}, baseFee, priorityFee)
+	if err != nil {
+		log.Println("authorize_fee_public error:", err)
+		return nil, errors.New("failed to authorize fee")
+	}
+
+	authorization, err = decodeAuthorization(result)
+	if err != nil {
+		return nil, err
+	}
+
+	// the fee transition must pay the requested fees
+	if !isFeePublicTransition(&authorization.Transitions[0], inputs) {
+		return nil, errors.New("module authorized another fee")
+	}
+
+	return authorization, nil
+}
+
+// feePublicInputs formats inputs of credits.aleo/fee_public: the base fee, the priority fee, and the execution ID
+func feePublicInputs(executionID string, baseFee uint64, priorityFee uint64) ([]string, error) {
+	if _, err := literal.ParseField(executionID); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidExecutionID, err)
+	}
+
+	// fee_public charges the sum of the fees, which must not overflow
+	if baseFee > math.MaxUint64-priorityFee {
+		return nil, errors.New("total fee overflows u64")
+	}
+
+	return []string{
+		literal.NewU64(baseFee).String(),
+		literal.NewU64(priorityFee).String(),
+		executionID,
+	}, nil
+}
+
+// isFeePublicTransition checks that a transition is a credits.aleo/fee_public call with the public inputs
+func isFeePublicTransition(transition *Transition, inputs []string) bool {
+	if transition.Program != "credits.aleo" || transition.Function != "fee_public" {
+		return false
+	}
+
+	values := make([]string, 0, len(transition.Inputs))
+	for _, input := range transition.Inputs {
+		if input.Type != "public" {
+			return false
+		}
+		values = append(values, input.Value)
+	}
+
+	return slices.Equal(values, inputs)
+}
@@ -0,0 +1,134 @@
+package field
+
+import "math/big"
+
+var baseParams = &params{
+	p:       limbs{0x0a11800000000001, 0x59aa76fed0000001, 0x60b44d1e5c37b001, 0x12ab655e9a2ca556},
+	r:       limbs{0x7d1c7ffffffffff3, 0x7257f50f6ffffff2, 0x16d81575512c0fee, 0x0d4bda322bbb9a9d},
+	r2:      limbs{0x25d577bab861857b, 0xcc2c27b58860591f, 0xa7cc008fe5dc8593, 0x011fdae7eff1c939},
+	inv:     0x0a117fffffffffff,
+	pMinus2: limbs{0x0a117fffffffffff, 0x59aa76fed0000001, 0x60b44d1e5c37b001, 0x12ab655e9a2ca556},
+	suffix:  "field",
+}
+
+// BaseModulus returns the modulus of the base field.
+func BaseModulus() *big.Int {
+	return baseParams.modulus()
+}
+
+// Base is an element of the Aleo base field, the `field` Leo type. The zero value is zero.
+type Base struct {
+	l limbs
+}
+
+// Zero sets v = 0 and returns v.
+func (v *Base) Zero() *Base {
+	v.l = limbs{}
+	return v
+}
+
+// One sets v = 1 and returns v.
+func (v *Base) One() *Base {
+	v.l = baseParams.r
+	return v
+}
+
+// Set sets v = a and returns v.
+func (v *Base) Set(a *Base) *Base {
+	v.l = a.l
+	return v
+}
+
+// SetUint64 sets v = x and returns v.
+func (v *Base) SetUint64(x uint64) *Base {
+	baseParams.toMont(&v.l, &limbs{x})
+	return v
+}
+
+// SetBytesLE sets v to 32 little-endian bytes and returns v. Returns ErrNotCanonical if the value is not less than
+// the modulus, v is not changed on error.
+func (v *Base) SetBytesLE(buf []byte) (*Base, error) {
+	if err := baseParams.setBytesLE(&v.l, buf); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// SetWideBytesLE sets v to little-endian bytes of any size reduced modulo the modulus and returns v,
+// e.g. to convert a hash to a field element.
+func (v *Base) SetWideBytesLE(buf []byte) *Base {
+	baseParams.setWideBytesLE(&v.l, buf)
+	return v
+}
+
+// SetString sets v to a Leo literal, e.g. `123field`, and returns v. v is not changed on error.
+func (v *Base) SetString(str string) (*Base, error) {
+	if err := baseParams.setString(&v.l, str); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// BytesLE returns 32 little-endian bytes of v.
+func (v *Base) BytesLE() []byte {
+	return baseParams.bytesLE(&v.l)
+}
+
+// String formats v as a Leo literal, e.g. `123field`.
+func (v *Base) String() string {
+	return baseParams.string(&v.l)
+}
+
+// Add sets v = a + b and returns v.
+func (v *Base) Add(a, b *Base) *Base {
+	baseParams.add(&v.l, &a.l, &b.l)
+	return v
+}
+
+// Sub sets v = a - b and returns v.
+func (v *Base) Sub(a, b *Base) *Base {
+	baseParams.sub(&v.l, &a.l, &b.l)
+	return v
+}
+
+// Neg sets v = -a and returns v.
+func (v *Base) Neg(a *Base) *Base {
+	baseParams.sub(&v.l, &limbs{}, &a.l)
+	return v
+}
+
+// Mul sets v = a * b and returns v.
+func (v *Base) Mul(a, b *Base) *Base {
+	baseParams.mul(&v.l, &a.l, &b.l)
+	return v
+}
+
+// Square sets v = a * a and returns v.
+func (v *Base) Square(a *Base) *Base {
+	baseParams.mul(&v.l, &a.l, &a.l)
+	return v
+}
+
+// Inverse sets v = 1 / a and returns v. The inverse of zero is zero.
+func (v *Base) Inverse(a *Base) *Base {
+	baseParams.exp(&v.l, &a.l, &baseParams.pMinus2)
+	return v
+}
+
+// Select sets v = a if cond == 1, and v = b if cond == 0, and returns v.
+func (v *Base) Select(a, b *Base, cond int) *Base {
+	selectLimbs(&v.l, &a.l, &b.l, uint64(cond))
+	return v
+}
+
+// Equal returns 1 if v == a, and 0 otherwise.
+func (v *Base) Equal(a *Base) int {
+	return equal(&v.l, &a.l)
+}
+
+// IsZero returns 1 if v == 0, and 0 otherwise.
+func (v *Base) IsZero() int {
+	return equal(&v.l, &limbs{})
+}
@@ -0,0 +1,335 @@
+// Package field implements constant-time arithmetic over the Aleo fields: the base field, which is the `field` Leo type,
+// and the scalar field, which is the `scalar` Leo type. The base field is the scalar field of BLS12-377, which
+// the Aleo Edwards curve is defined over, the scalar field is the field of the Edwards curve prime-order subgroup.
+//
+// Elements are stored in the Montgomery form as 4 64-bit limbs. Arithmetic doesn't branch on or index memory by
+// secret values. Parsing and printing of literals run in time, which depends only on the literal length.
+package field
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"strings"
+)
+
+var (
+	ErrInvalidLiteral = errors.New("invalid field literal")
+	ErrNotCanonical   = errors.New("value is not less than the field modulus")
+)
+
+// Size is the size of an encoded element in bytes
+const Size = 32
+
+type limbs [4]uint64
+
+// params of a prime field with a modulus less than 2^254
+type params struct {
+	// modulus
+	p limbs
+	// 2^256 mod p, the Montgomery form of 1
+	r limbs
+	// 2^512 mod p, used to convert to the Montgomery form
+	r2 limbs
+	// -p^-1 mod 2^64
+	inv uint64
+	// p - 2, the exponent of the inverse
+	pMinus2 limbs
+	// suffix of the Leo literals
+	suffix string
+}
+
+// condSub sets z = t - p if (hi, t) >= p, and z = t otherwise
+func (fp *params) condSub(z *limbs, hi uint64, t *limbs) {
+	var d limbs
+	var b uint64
+	d[0], b = bits.Sub64(t[0], fp.p[0], 0)
+	d[1], b = bits.Sub64(t[1], fp.p[1], b)
+	d[2], b = bits.Sub64(t[2], fp.p[2], b)
+	d[3], b = bits.Sub64(t[3], fp.p[3], b)
+	_, b = bits.Sub64(hi, 0, b)
+
+	// keep t if the subtraction borrowed
+	mask := -b
+	for i := range z {
+		z[i] = t[i]&mask | d[i]&^mask
+	}
+}
+
+// add sets z = x + y mod p
+func (fp *params) add(z, x, y *limbs) {
+	var t limbs
+	var c uint64
+	t[0], c = bits.Add64(x[0], y[0], 0)
+	t[1], c = bits.Add64(x[1], y[1], c)
+	t[2], c = bits.Add64(x[2], y[2], c)
+	t[3], c = bits.Add64(x[3], y[3], c)
+
+	fp.condSub(z, c, &t)
+}
+
+// sub sets z = x - y mod p
+func (fp *params) sub(z, x, y *limbs) {
+	var t limbs
+	var b uint64
+	t[0], b = bits.Sub64(x[0], y[0], 0)
+	t[1], b = bits.Sub64(x[1], y[1], b)
+	t[2], b = bits.Sub64(x[2], y[2], b)
+	t[3], b = bits.Sub64(x[3], y[3], b)
+
+	// add p back if the subtraction borrowed
+	mask := -b
+	var c uint64
+	z[0], c = bits.Add64(t[0], fp.p[0]&mask, 0)
+	z[1], c = bits.Add64(t[1], fp.p[1]&mask, c)
+	z[2], c = bits.Add64(t[2], fp.p[2]&mask, c)
+	z[3], _ = bits.Add64(t[3], fp.p[3]&mask, c)
+}
+
+// mul sets z = x * y / 2^256 mod p using the CIOS Montgomery multiplication
+func (fp *params) mul(z, x, y *limbs) {
+	var t [6]uint64
+	for i := 0; i < 4; i++ {
+		// t += x * y[i]
+		var c uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(x[j], y[i])
+			var carry uint64
+			lo, carry = bits.Add64(lo, t[j], 0)
+			hi += carry
+			lo, carry = bits.Add64(lo, c, 0)
+			hi += carry
+			t[j] = lo
+			c = hi
+		}
+		var carry uint64
+		t[4], carry = bits.Add64(t[4], c, 0)
+		t[5] = carry
+
+		// t = (t + m * p) / 2^64, where m makes the lowest limb zero
+		m := t[0] * fp.inv
+		hi, lo := bits.Mul64(m, fp.p[0])
+		_, carry = bits.Add64(lo, t[0], 0)
+		c = hi + carry
+		for j := 1; j < 4; j++ {
+			hi, lo = bits.Mul64(m, fp.p[j])
+			lo, carry = bits.Add64(lo, t[j], 0)
+			hi += carry
+			lo, carry = bits.Add64(lo, c, 0)
+			hi += carry
+			t[j-1] = lo
+			c = hi
+		}
+		t[3], carry = bits.Add64(t[4], c, 0)
+		t[4] = t[5] + carry
+	}
+
+	fp.condSub(z, t[4], (*limbs)(t[:4]))
+}
+
+// exp sets z = x^e, the exponent is public
+func (fp *params) exp(z, x *limbs, e *limbs) {
+	result := fp.r
+	base := *x
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 64; j++ {
+			var product limbs
+			fp.mul(&product, &result, &base)
+			selectLimbs(&result, &product, &result, (e[i]>>j)&1)
+			fp.mul(&base, &base, &base)
+		}
+	}
+
+	*z = result
+}
+
+// toMont converts a canonical value to the Montgomery form
+func (fp *params) toMont(z, x *limbs) {
+	fp.mul(z, x, &fp.r2)
+}
+
+// fromMont converts a value from the Montgomery form
+func (fp *params) fromMont(z, x *limbs) {
+	one := limbs{1}
+	fp.mul(z, x, &one)
+}
+
+// isCanonical returns 1 if x < p, and 0 otherwise
+func (fp *params) isCanonical(x *limbs) uint64 {
+	var b uint64
+	_, b = bits.Sub64(x[0], fp.p[0], 0)
+	_, b = bits.Sub64(x[1], fp.p[1], b)
+	_, b = bits.Sub64(x[2], fp.p[2], b)
+	_, b = bits.Sub64(x[3], fp.p[3], b)
+
+	return b
+}
+
+// setBytesLE sets z to the Montgomery form of 32 little-endian bytes, which must be less than p
+func (fp *params) setBytesLE(z *limbs, buf []byte) error {
+	if len(buf) != Size {
+		return fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidLiteral, Size, len(buf))
+	}
+
+	x := limbsFromBytesLE(buf)
+	if fp.isCanonical(&x) == 0 {
+		return ErrNotCanonical
+	}
+
+	fp.toMont(z, &x)
+	return nil
+}
+
+// modulus returns the modulus as an integer
+func (fp *params) modulus() *big.Int {
+	buf := make([]byte, Size)
+	for i, limb := range fp.p {
+		for j := 0; j < 8; j++ {
+			buf[Size-1-8*i-j] = byte(limb >> (8 * j))
+		}
+	}
+
+	return new(big.Int).SetBytes(buf)
+}
+
+// setWideBytesLE sets z to little-endian bytes of any size reduced modulo p
+func (fp *params) setWideBytesLE(z *limbs, buf []byte) {
+	// 2^64 in the Montgomery form
+	var shift limbs
+	fp.mul(&shift, &fp.r2, &limbs{0, 1})
+
+	var acc limbs
+	// chunks are aligned to the start of the buffer, the most significant one may be shorter
+	for start := (len(buf) - 1) / 8 * 8; start >= 0; start -= 8 {
+		var chunk [8]byte
+		copy(chunk[:], buf[start:min(start+8, len(buf))])
+
+		// acc = acc * 2^64 + chunk
+		var word limbs
+		word[0] = leUint64(chunk[:])
+		fp.toMont(&word, &word)
+		fp.mul(&acc, &acc, &shift)
+		fp.add(&acc, &acc, &word)
+	}
+
+	*z = acc
+}
+
+// bytesLE returns 32 little-endian bytes of a value in the Montgomery form
+func (fp *params) bytesLE(x *limbs) []byte {
+	var canonical limbs
+	fp.fromMont(&canonical, x)
+
+	buf := make([]byte, Size)
+	for i, limb := range canonical {
+		for j := 0; j < 8; j++ {
+			buf[8*i+j] = byte(limb >> (8 * j))
+		}
+	}
+
+	return buf
+}
+
+// setString parses a decimal Leo literal with the field suffix, e.g. `123field`
+func (fp *params) setString(z *limbs, str string) error {
+	digits, ok := strings.CutSuffix(str, fp.suffix)
+	if !ok || len(digits) == 0 {
+		return fmt.Errorf("%w: %q is not a %s literal", ErrInvalidLiteral, str, fp.suffix)
+	}
+
+	// x = x * 10 + digit, overflow is accumulated in the fifth limb
+	var x limbs
+	var overflow uint64
+	for i := 0; i < len(digits); i++ {
+		c := digits[i]
+		if c == '_' && i > 0 {
+			continue
+		}
+		if c < '0' || c > '9' {
+			return fmt.Errorf("%w: %q is not a %s literal", ErrInvalidLiteral, str, fp.suffix)
+		}
+
+		carry := uint64(c - '0')
+		for j := range x {
+			hi, lo := bits.Mul64(x[j], 10)
+			var c uint64
+			x[j], c = bits.Add64(lo, carry, 0)
+			carry = hi + c
+		}
+		overflow |= carry
+	}
+
+	if overflow != 0 || fp.isCanonical(&x) == 0 {
+		return fmt.Errorf("%w: %q", ErrNotCanonical, str)
+	}
+
+	fp.toMont(z, &x)
+	return nil
+}
+
+// string formats a value in the Montgomery form as a decimal Leo literal
+func (fp *params) string(x *limbs) string {
+	var v limbs
+	fp.fromMont(&v, x)
+
+	// 10^95 > 2^256, so 5 chunks of 19 digits are enough
+	const chunkDivisor = 10_000_000_000_000_000_000
+	var chunks [5]uint64
+	for i := range chunks {
+		var rem uint64
+		for j := 3; j >= 0; j-- {
+			v[j], rem = bits.Div64(rem, v[j], chunkDivisor)
+		}
+		chunks[i] = rem
+	}
+
+	var sb strings.Builder
+	for i := len(chunks) - 1; i >= 0; i-- {
+		fmt.Fprintf(&sb, "%019d", chunks[i])
+	}
+
+	digits := strings.TrimLeft(sb.String(), "0")
+	if digits == "" {
+		digits = "0"
+	}
+
+	return digits + fp.suffix
+}
+
+// selectLimbs sets z = a if cond == 1, and z = b if cond == 0
+func selectLimbs(z, a, b *limbs, cond uint64) {
+	mask := -cond
+	for i := range z {
+		z[i] = a[i]&mask | b[i]&^mask
+	}
+}
+
+// equal returns 1 if x == y, and 0 otherwise
+func equal(x, y *limbs) int {
+	var diff uint64
+	for i := range x {
+		diff |= x[i] ^ y[i]
+	}
+
+	// the top bit of diff | -diff is set if diff is not zero
+	return int(1 ^ (diff|-diff)>>63)
+}
+
+func leUint64(buf []byte) uint64 {
+	var v uint64
+	for i := 7; i >= 0; i-- {
+		v = v<<8 | uint64(buf[i])
+	}
+
+	return v
+}
+
+func limbsFromBytesLE(buf []byte) limbs {
+	var x limbs
+	for i := range x {
+		x[i] = leUint64(buf[8*i:])
+	}
+
+	return x
+}
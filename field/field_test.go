@@ -0,0 +1,385 @@
+package field
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+var (
+	baseModulus, _   = new(big.Int).SetString("8444461749428370424248824938781546531375899335154063827935233455917409239041", 10)
+	scalarModulus, _ = new(big.Int).SetString("2111115437357092606062206234695386632838870926408408195193685246394721360383", 10)
+)
+
+// element is a field element used as a test case, which is generated from random values of the whole field range
+type element struct {
+	v *big.Int
+}
+
+func (element) Generate(r *rand.Rand, size int) reflect.Value {
+	buf := make([]byte, Size)
+	r.Read(buf)
+
+	// mix in edge values, which random values rarely hit
+	switch r.Intn(8) {
+	case 0:
+		clear(buf)
+	case 1:
+		clear(buf)
+		buf[0] = 1
+	case 2:
+		for i := range buf {
+			buf[i] = 0xff
+		}
+	}
+
+	return reflect.ValueOf(element{v: new(big.Int).SetBytes(buf)})
+}
+
+// testField runs the same property tests for both fields
+type testField[T any] struct {
+	modulus *big.Int
+	suffix  string
+	// creates an element from a canonical value
+	fromBig func(*big.Int) *T
+	toBig   func(*T) *big.Int
+	add     func(a, b *T) *T
+	sub     func(a, b *T) *T
+	mul     func(a, b *T) *T
+	neg     func(a *T) *T
+	square  func(a *T) *T
+	inverse func(a *T) *T
+	str     func(a *T) string
+	parse   func(string) (*T, error)
+}
+
+func leBytes(v *big.Int) []byte {
+	buf := make([]byte, Size)
+	v.FillBytes(buf)
+
+	for i, j := 0, len(buf)-1; i < j; i, j = i+1, j-1 {
+		buf[i], buf[j] = buf[j], buf[i]
+	}
+
+	return buf
+}
+
+func beBytes(buf []byte) []byte {
+	be := bytes.Clone(buf)
+	for i, j := 0, len(be)-1; i < j; i, j = i+1, j-1 {
+		be[i], be[j] = be[j], be[i]
+	}
+
+	return be
+}
+
+func (f testField[T]) run(t *testing.T) {
+	mod := func(v *big.Int) *big.Int {
+		return v.Mod(v, f.modulus)
+	}
+	reduce := func(e element) *big.Int {
+		return mod(new(big.Int).Set(e.v))
+	}
+
+	config := &quick.Config{MaxCount: 1000}
+
+	props := map[string]any{
+		"add": func(a, b element) bool {
+			x, y := reduce(a), reduce(b)
+			return f.toBig(f.add(f.fromBig(x), f.fromBig(y))).Cmp(mod(new(big.Int).Add(x, y))) == 0
+		},
+		"sub": func(a, b element) bool {
+			x, y := reduce(a), reduce(b)
+			return f.toBig(f.sub(f.fromBig(x), f.fromBig(y))).Cmp(mod(new(big.Int).Sub(x, y))) == 0
+		},
+		"mul": func(a, b element) bool {
+			x, y := reduce(a), reduce(b)
+			return f.toBig(f.mul(f.fromBig(x), f.fromBig(y))).Cmp(mod(new(big.Int).Mul(x, y))) == 0
+		},
+		"neg": func(a element) bool {
+			x := reduce(a)
+			return f.toBig(f.neg(f.fromBig(x))).Cmp(mod(new(big.Int).Neg(x))) == 0
+		},
+		"square": func(a element) bool {
+			x := reduce(a)
+			return f.toBig(f.square(f.fromBig(x))).Cmp(f.toBig(f.mul(f.fromBig(x), f.fromBig(x)))) == 0
+		},
+		"inverse": func(a element) bool {
+			x := reduce(a)
+			want := new(big.Int).ModInverse(x, f.modulus)
+			if want == nil {
+				want = new(big.Int)
+			}
+			return f.toBig(f.inverse(f.fromBig(x))).Cmp(want) == 0
+		},
+		"distributive": func(a, b, c element) bool {
+			x, y, z := f.fromBig(reduce(a)), f.fromBig(reduce(b)), f.fromBig(reduce(c))
+			left := f.mul(x, f.add(y, z))
+			right := f.add(f.mul(x, y), f.mul(x, z))
+			return f.toBig(left).Cmp(f.toBig(right)) == 0
+		},
+		"literal": func(a element) bool {
+			x := reduce(a)
+			str := f.str(f.fromBig(x))
+			if str != x.String()+f.suffix {
+				return false
+			}
+			parsed, err := f.parse(str)
+			return err == nil && f.toBig(parsed).Cmp(x) == 0
+		},
+	}
+	for name, prop := range props {
+		t.Run(name, func(t *testing.T) {
+			if err := quick.Check(prop, config); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	maxValue := new(big.Int).Sub(f.modulus, big.NewInt(1))
+	invalid := []struct {
+		str     string
+		wantErr error
+	}{
+		{f.modulus.String() + f.suffix, ErrNotCanonical},
+		{new(big.Int).Lsh(big.NewInt(1), 256).String() + f.suffix, ErrNotCanonical},
+		{maxValue.String(), ErrInvalidLiteral},
+		{"-1" + f.suffix, ErrInvalidLiteral},
+		{f.suffix, ErrInvalidLiteral},
+		{"1u8", ErrInvalidLiteral},
+	}
+	for _, tt := range invalid {
+		if _, err := f.parse(tt.str); !errors.Is(err, tt.wantErr) {
+			t.Errorf("SetString(%q) error = %v, want %v", tt.str, err, tt.wantErr)
+		}
+	}
+
+	if parsed, err := f.parse("1_000" + f.suffix); err != nil || f.toBig(parsed).Int64() != 1000 {
+		t.Errorf("SetString() with underscores = %v, %v", parsed, err)
+	}
+}
+
+func TestBase(t *testing.T) {
+	testField[Base]{
+		modulus: baseModulus,
+		suffix:  "field",
+		fromBig: func(v *big.Int) *Base {
+			e, err := new(Base).SetBytesLE(leBytes(v))
+			if err != nil {
+				panic(err)
+			}
+			return e
+		},
+		toBig:   func(e *Base) *big.Int { return new(big.Int).SetBytes(beBytes(e.BytesLE())) },
+		add:     func(a, b *Base) *Base { return new(Base).Add(a, b) },
+		sub:     func(a, b *Base) *Base { return new(Base).Sub(a, b) },
+		mul:     func(a, b *Base) *Base { return new(Base).Mul(a, b) },
+		neg:     func(a *Base) *Base { return new(Base).Neg(a) },
+		square:  func(a *Base) *Base { return new(Base).Square(a) },
+		inverse: func(a *Base) *Base { return new(Base).Inverse(a) },
+		str:     func(a *Base) string { return a.String() },
+		parse:   func(str string) (*Base, error) { return new(Base).SetString(str) },
+	}.run(t)
+}
+
+func TestScalar(t *testing.T) {
+	testField[Scalar]{
+		modulus: scalarModulus,
+		suffix:  "scalar",
+		fromBig: func(v *big.Int) *Scalar {
+			e, err := new(Scalar).SetBytesLE(leBytes(v))
+			if err != nil {
+				panic(err)
+			}
+			return e
+		},
+		toBig:   func(e *Scalar) *big.Int { return new(big.Int).SetBytes(beBytes(e.BytesLE())) },
+		add:     func(a, b *Scalar) *Scalar { return new(Scalar).Add(a, b) },
+		sub:     func(a, b *Scalar) *Scalar { return new(Scalar).Sub(a, b) },
+		mul:     func(a, b *Scalar) *Scalar { return new(Scalar).Mul(a, b) },
+		neg:     func(a *Scalar) *Scalar { return new(Scalar).Neg(a) },
+		square:  func(a *Scalar) *Scalar { return new(Scalar).Square(a) },
+		inverse: func(a *Scalar) *Scalar { return new(Scalar).Inverse(a) },
+		str:     func(a *Scalar) string { return a.String() },
+		parse:   func(str string) (*Scalar, error) { return new(Scalar).SetString(str) },
+	}.run(t)
+}
+
+func TestConversions(t *testing.T) {
+	var one Base
+	one.One()
+	if one.String() != "1field" || one.IsZero() != 0 {
+		t.Errorf("Base.One() = %s", one.String())
+	}
+
+	var zero Base
+	if zero.String() != "0field" || zero.IsZero() != 1 || zero.Equal(new(Base).Zero()) != 1 {
+		t.Errorf("Base zero value = %s", zero.String())
+	}
+
+	if got := new(Base).SetUint64(1 << 63).String(); got != "9223372036854775808field" {
+		t.Errorf("Base.SetUint64() = %s", got)
+	}
+
+	// any u128 fits both fields
+	maxU128 := bytes.Repeat([]byte{0xff}, 16)
+	if got := new(Scalar).SetWideBytesLE(maxU128).String(); got != "340282366920938463463374607431768211455scalar" {
+		t.Errorf("Scalar.SetWideBytesLE() = %s", got)
+	}
+
+	// wide values are reduced modulo the field modulus
+	wide := bytes.Repeat([]byte{0xab}, 80)
+	want := new(big.Int).Mod(new(big.Int).SetBytes(beBytes(wide)), scalarModulus)
+	if got := new(Scalar).SetWideBytesLE(wide).String(); got != want.String()+"scalar" {
+		t.Errorf("Scalar.SetWideBytesLE() = %s, want %sscalar", got, want)
+	}
+
+	// lengths, which are not a multiple of 8
+	for n := 0; n <= len(wide); n++ {
+		buf := make([]byte, n)
+		for i := range buf {
+			buf[i] = byte(i*37 + n)
+		}
+		want := new(big.Int).Mod(new(big.Int).SetBytes(beBytes(buf)), baseModulus)
+		if got := new(Base).SetWideBytesLE(buf).String(); got != want.String()+"field" {
+			t.Errorf("Base.SetWideBytesLE() of %d bytes = %s, want %sfield", n, got, want)
+		}
+	}
+
+	if _, err := new(Base).SetBytesLE(leBytes(baseModulus)); !errors.Is(err, ErrNotCanonical) {
+		t.Errorf("Base.SetBytesLE() error = %v, want %v", err, ErrNotCanonical)
+	}
+	if _, err := new(Base).SetBytesLE(make([]byte, Size-1)); err == nil {
+		t.Error("Base.SetBytesLE() should fail with wrong size")
+	}
+
+	if BaseModulus().Cmp(baseModulus) != 0 || ScalarModulus().Cmp(scalarModulus) != 0 {
+		t.Errorf("moduli = %s, %s", BaseModulus(), ScalarModulus())
+	}
+
+	a, b := new(Base).SetUint64(1), new(Base).SetUint64(2)
+	if new(Base).Select(a, b, 1).Equal(a) != 1 || new(Base).Select(a, b, 0).Equal(b) != 1 {
+		t.Error("Base.Select() returned a wrong element")
+	}
+}
+
+// reference vectors computed with arbitrary-precision integers, the same values as snarkVM's field operations
+var vectors = []struct {
+	a, b, add, sub, mul, neg, square, inverse string
+}{
+	{"8023744830061670308860526827577223901395951373278016967024894657227776806653field", "3993777717328553126384006574550784763443939190367281835954784772918614398141field", "3573060797961853010995708463346462133463991228491234975044445974228981965753field", "4029967112733117182476520253026439137952012182910735131070109884309162408512field", "6829310992696118584253271478146565159698711120853714114364859179956543179033field", "420716919366700115388298111204322629979947961876046860910338798689632432388field", "8065395279765192747560398525456310298926342474645533326837409910180394007844field", "5373887514608464194494369023374726590792667835501732166334706756519803208053field"},
+	{"8444461749428370424248824938781546531375899335154063827935233455917409239040field", "2field", "1field", "8444461749428370424248824938781546531375899335154063827935233455917409239038field", "8444461749428370424248824938781546531375899335154063827935233455917409239039field", "1field", "1field", "8444461749428370424248824938781546531375899335154063827935233455917409239040field"},
+	{"3172257424475378957760239647131183559014936279383811380243182712916352923396field", "248048637496024956670246272861409062498field", "3172257424475378957760239647131183559262984916879836336913428985777761985894field", "3172257424475378957760239647131183558766887641887786423572936440054943860898field", "5661300517079733508288290933716473249430955613155828931363376201922886695147field", "5272204324952991466488585291650362972360963055770252447692050743001056315645field", "6870685870027446924196438185193532138538614776170251840677809107380165826406field", "554318783537859446587108743912248863027433686562652289190048272981911107626field"},
+	{"922790704336712165155892264277838031927218561084461472042101068252944121312scalar", "804060106823462116529898856472616013433467895111369678932264721851678820468scalar", "1726850811160174281685791120750454045360686456195831150974365790104622941780scalar", "118730597513250048625993407805222018493750665973091793109836346401265300844scalar", "1040431929073747232744149815379071846130587907236700929754956453322634576462scalar", "1188324733020380440906313970417548600911652365323946723151584178141777239071scalar", "31207329344809536337941718595271275019241711793294234863211083280148967981scalar", "1259263526411309755100883270796976683948701625842661326035216346126365390512scalar"},
+	{"2111115437357092606062206234695386632838870926408408195193685246394721360382scalar", "2scalar", "1scalar", "2111115437357092606062206234695386632838870926408408195193685246394721360380scalar", "2111115437357092606062206234695386632838870926408408195193685246394721360381scalar", "1scalar", "1scalar", "2111115437357092606062206234695386632838870926408408195193685246394721360382scalar"},
+	{"721280913617754790107133907904947052737768037886184613071264114552585691902scalar", "225931273563332820007978739263817885658scalar", "721280913617754790107133907904947052963699311449517433079242853816403577560scalar", "721280913617754790107133907904947052511836764322851793063285375288767806244scalar", "1485289111287381039442169979004140701135724747550225957110763046202710580758scalar", "1389834523739337815955072326790439580101102888522223582122421131842135668481scalar", "573893604601362570438312877272076739513828448234565735965327272497813607746scalar", "712367552327511189616297520778153055453361423811326125361482588257163846873scalar"},
+}
+
+func TestVectors(t *testing.T) {
+	type ops struct {
+		add, sub, mul        func(a, b string) (string, error)
+		neg, square, inverse func(a string) (string, error)
+	}
+
+	base := func(op func(z, a, b *Base)) func(a, b string) (string, error) {
+		return func(a, b string) (string, error) {
+			x, err := new(Base).SetString(a)
+			if err != nil {
+				return "", err
+			}
+			y, err := new(Base).SetString(b)
+			if err != nil {
+				return "", err
+			}
+			z := new(Base)
+			op(z, x, y)
+			return z.String(), nil
+		}
+	}
+	scalar := func(op func(z, a, b *Scalar)) func(a, b string) (string, error) {
+		return func(a, b string) (string, error) {
+			x, err := new(Scalar).SetString(a)
+			if err != nil {
+				return "", err
+			}
+			y, err := new(Scalar).SetString(b)
+			if err != nil {
+				return "", err
+			}
+			z := new(Scalar)
+			op(z, x, y)
+			return z.String(), nil
+		}
+	}
+	unary := func(op func(a, b string) (string, error)) func(a string) (string, error) {
+		return func(a string) (string, error) { return op(a, a) }
+	}
+
+	fields := map[string]ops{
+		"field": {
+			add:     base(func(z, a, b *Base) { z.Add(a, b) }),
+			sub:     base(func(z, a, b *Base) { z.Sub(a, b) }),
+			mul:     base(func(z, a, b *Base) { z.Mul(a, b) }),
+			neg:     unary(base(func(z, a, _ *Base) { z.Neg(a) })),
+			square:  unary(base(func(z, a, _ *Base) { z.Square(a) })),
+			inverse: unary(base(func(z, a, _ *Base) { z.Inverse(a) })),
+		},
+		"scalar": {
+			add:     scalar(func(z, a, b *Scalar) { z.Add(a, b) }),
+			sub:     scalar(func(z, a, b *Scalar) { z.Sub(a, b) }),
+			mul:     scalar(func(z, a, b *Scalar) { z.Mul(a, b) }),
+			neg:     unary(scalar(func(z, a, _ *Scalar) { z.Neg(a) })),
+			square:  unary(scalar(func(z, a, _ *Scalar) { z.Square(a) })),
+			inverse: unary(scalar(func(z, a, _ *Scalar) { z.Inverse(a) })),
+		},
+	}
+
+	for _, v := range vectors {
+		f := fields["field"]
+		if strings.HasSuffix(v.a, "scalar") {
+			f = fields["scalar"]
+		}
+
+		results := []struct {
+			name string
+			got  func() (string, error)
+			want string
+		}{
+			{"add", func() (string, error) { return f.add(v.a, v.b) }, v.add},
+			{"sub", func() (string, error) { return f.sub(v.a, v.b) }, v.sub},
+			{"mul", func() (string, error) { return f.mul(v.a, v.b) }, v.mul},
+			{"neg", func() (string, error) { return f.neg(v.a) }, v.neg},
+			{"square", func() (string, error) { return f.square(v.a) }, v.square},
+			{"inverse", func() (string, error) { return f.inverse(v.a) }, v.inverse},
+		}
+		for _, r := range results {
+			got, err := r.got()
+			if err != nil {
+				t.Fatalf("%s(%s, %s) error = %v", r.name, v.a, v.b, err)
+			}
+			if got != r.want {
+				t.Errorf("%s(%s, %s) = %s, want %s", r.name, v.a, v.b, got, r.want)
+			}
+		}
+	}
+}
+
+func BenchmarkBaseMul(b *testing.B) {
+	x, _ := new(Base).SetString("1234567890123456789012345678901234567890field")
+	y := new(Base).Set(x)
+	for i := 0; i < b.N; i++ {
+		y.Mul(y, x)
+	}
+}
+
+func BenchmarkBaseInverse(b *testing.B) {
+	x, _ := new(Base).SetString("1234567890123456789012345678901234567890field")
+	for i := 0; i < b.N; i++ {
+		x.Inverse(x)
+	}
+}
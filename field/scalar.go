@@ -0,0 +1,134 @@
+package field
+
+import "math/big"
+
+var scalarParams = &params{
+	p:       limbs{0xb95aee9ac33fd9ff, 0x5293a3afc43c8afe, 0x982d1347970dec00, 0x04aad957a68b2955},
+	r:       limbs{0xe6d1ab5ad0880436, 0x94db78ec9b3aae44, 0xe67deee6231037ee, 0x03f62782dea547f1},
+	r2:      limbs{0x375699cd6a55d45e, 0xf639c3f57a73da73, 0xca06049ccd027a21, 0x047ada1eef02d841},
+	inv:     0x860efbdd70e3da01,
+	pMinus2: limbs{0xb95aee9ac33fd9fd, 0x5293a3afc43c8afe, 0x982d1347970dec00, 0x04aad957a68b2955},
+	suffix:  "scalar",
+}
+
+// ScalarModulus returns the modulus of the scalar field.
+func ScalarModulus() *big.Int {
+	return scalarParams.modulus()
+}
+
+// Scalar is an element of the Aleo scalar field, the `scalar` Leo type. The zero value is zero.
+type Scalar struct {
+	l limbs
+}
+
+// Zero sets v = 0 and returns v.
+func (v *Scalar) Zero() *Scalar {
+	v.l = limbs{}
+	return v
+}
+
+// One sets v = 1 and returns v.
+func (v *Scalar) One() *Scalar {
+	v.l = scalarParams.r
+	return v
+}
+
+// Set sets v = a and returns v.
+func (v *Scalar) Set(a *Scalar) *Scalar {
+	v.l = a.l
+	return v
+}
+
+// SetUint64 sets v = x and returns v.
+func (v *Scalar) SetUint64(x uint64) *Scalar {
+	scalarParams.toMont(&v.l, &limbs{x})
+	return v
+}
+
+// SetBytesLE sets v to 32 little-endian bytes and returns v. Returns ErrNotCanonical if the value is not less than
+// the modulus, v is not changed on error.
+func (v *Scalar) SetBytesLE(buf []byte) (*Scalar, error) {
+	if err := scalarParams.setBytesLE(&v.l, buf); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// SetWideBytesLE sets v to little-endian bytes of any size reduced modulo the modulus and returns v,
+// e.g. to convert a hash to a scalar.
+func (v *Scalar) SetWideBytesLE(buf []byte) *Scalar {
+	scalarParams.setWideBytesLE(&v.l, buf)
+	return v
+}
+
+// SetString sets v to a Leo literal, e.g. `123scalar`, and returns v. v is not changed on error.
+func (v *Scalar) SetString(str string) (*Scalar, error) {
+	if err := scalarParams.setString(&v.l, str); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// BytesLE returns 32 little-endian bytes of v.
+func (v *Scalar) BytesLE() []byte {
+	return scalarParams.bytesLE(&v.l)
+}
+
+// String formats v as a Leo literal, e.g. `123scalar`.
+func (v *Scalar) String() string {
+	return scalarParams.string(&v.l)
+}
+
+// Add sets v = a + b and returns v.
+func (v *Scalar) Add(a, b *Scalar) *Scalar {
+	scalarParams.add(&v.l, &a.l, &b.l)
+	return v
+}
+
+// Sub sets v = a - b and returns v.
+func (v *Scalar) Sub(a, b *Scalar) *Scalar {
+	scalarParams.sub(&v.l, &a.l, &b.l)
+	return v
+}
+
+// Neg sets v = -a and returns v.
+func (v *Scalar) Neg(a *Scalar) *Scalar {
+	scalarParams.sub(&v.l, &limbs{}, &a.l)
+	return v
+}
+
+// Mul sets v = a * b and returns v.
+func (v *Scalar) Mul(a, b *Scalar) *Scalar {
+	scalarParams.mul(&v.l, &a.l, &b.l)
+	return v
+}
+
+// Square sets v = a * a and returns v.
+func (v *Scalar) Square(a *Scalar) *Scalar {
+	scalarParams.mul(&v.l, &a.l, &a.l)
+	return v
+}
+
+// Inverse sets v = 1 / a and returns v. The inverse of zero is zero.
+func (v *Scalar) Inverse(a *Scalar) *Scalar {
+	scalarParams.exp(&v.l, &a.l, &scalarParams.pMinus2)
+	return v
+}
+
+// Select sets v = a if cond == 1, and v = b if cond == 0, and returns v.
+func (v *Scalar) Select(a, b *Scalar, cond int) *Scalar {
+	selectLimbs(&v.l, &a.l, &b.l, uint64(cond))
+	return v
+}
+
+// Equal returns 1 if v == a, and 0 otherwise.
+func (v *Scalar) Equal(a *Scalar) int {
+	return equal(&v.l, &a.l)
+}
+
+// IsZero returns 1 if v == 0, and 0 otherwise.
+func (v *Scalar) IsZero() int {
+	return equal(&v.l, &limbs{})
+}
@@ -0,0 +1,201 @@
+package aleo_utils
+
+import (
+	"errors"
+	"log"
+
+	"github.com/zkportal/aleo-utils-go/codec"
+)
+
+var ErrKeyReleased = errors.New("key handle is released")
+
+// KeyHandle is an opaque reference to a private key loaded into a session with LoadKey. The key is parsed once
+// and kept inside of the session module, so signing with a handle is cheaper than with a key string, and the key
+// doesn't cross the module boundary on every call.
+//
+// A handle can only be used with the session, which created it. The key is wiped from the module memory when
+// the handle is released or the session is closed.
+type KeyHandle struct {
+	id      uint32
+	session *aleoWrapperSession
+}
+
+// Release wipes the key from the session module memory. The handle cannot be used after it's released.
+func (h *KeyHandle) Release() (err error) {
+	if h.id == 0 {
+		return ErrKeyReleased
+	}
+
+	s := h.session
+	if s.mod == nil || s.mod.IsClosed() {
+		return ErrNoModule
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// find out exactly what the error was and set err
+			switch x := r.(type) {
+			case string:
+				err = errors.New(x)
+			case error:
+				err = x
+			default:
+				err = errors.New("unknown panic")
+			}
+		}
+	}()
+
+	result, err := s.call(s.releaseKey, uint64(h.id))
+	if err != nil {
+		log.Println("release_key error:", err)
+		return errors.New("failed to release key")
+	}
+	delete(s.handles, h.id)
+	h.id = 0
+
+	if result != 1 {
+		return errors.New("invalid key handle")
+	}
+
+	return nil
+}
+
+// LoadKey parses a private key inside of the session module and returns a handle to it, which can be used
+// for signing with SignWithHandle and SignWithHandleOptions. Release the handle when it's no longer needed.
+func (s *aleoWrapperSession) LoadKey(key string) (handle *KeyHandle, err error) {
+	if s.mod == nil || s.mod.IsClosed() {
+		return nil, ErrNoModule
+	}
+
+	if s.loadKey == nil {
+		return nil, ErrNotSupported
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// find out exactly what the error was and set err
+			switch x := r.(type) {
+			case string:
+				err = errors.New(x)
+			case error:
+				err = x
+			default:
+				err = errors.New("unknown panic")
+			}
+			handle = nil
+		}
+	}()
+
+	if len(key) != PRIVATE_KEY_SIZE {
+		return nil, errors.New("invalid private key size")
+	}
+	if err := codec.ValidatePrivateKey(key); err != nil {
+		return nil, err
+	}
+
+	if err := s.resetArena(PRIVATE_KEY_SIZE); err != nil {
+		log.Println("arena error:", err)
+		return nil, errors.New("failed to allocate memory for private key")
+	}
+
+	// write private key to wasm memory
+	privateKeyPtr, err := s.putString(key)
+	if err != nil {
+		return nil, errors.New("failed to write private key to memory")
+	}
+
+	id, err := s.call(s.loadKey, privateKeyPtr, PRIVATE_KEY_SIZE)
+
+	// the key is parsed by now, wipe it from the arena
+	s.mod.Memory().Write(uint32(privateKeyPtr), make([]byte, PRIVATE_KEY_SIZE))
+
+	if err != nil {
+		log.Println("load_key error:", err)
+		return nil, errors.New("failed to load private key")
+	}
+	if id == 0 {
+		return nil, errors.New("invalid private key")
+	}
+
+	handle = &KeyHandle{
+		id:      uint32(id),
+		session: s,
+	}
+	if s.handles == nil {
+		s.handles = make(map[uint32]*KeyHandle)
+	}
+	s.handles[handle.id] = handle
+
+	return handle, nil
+}
+
+// SignWithHandle is like Sign, but uses a key loaded with LoadKey. It uses the signing options of the wrapper.
+func (s *aleoWrapperSession) SignWithHandle(handle *KeyHandle, message []byte) (signature string, err error) {
+	return s.SignWithHandleOptions(handle, message, s.signOptions)
+}
+
+// SignWithHandleOptions is like SignWithHandle, but uses the provided options instead of the wrapper ones.
+func (s *aleoWrapperSession) SignWithHandleOptions(handle *KeyHandle, message []byte, opts SignOptions) (signature string, err error) {
+	if s.mod == nil || s.mod.IsClosed() {
+		return "", ErrNoModule
+	}
+
+	if s.signWithKey == nil {
+		return "", ErrNotSupported
+	}
+
+	if opts.DeterministicNonce && !s.deterministicNonce {
+		return "", ErrNotSupported
+	}
+
+	if handle == nil || handle.session != s {
+		return "", errors.New("key handle doesn't belong to the session")
+	}
+
+	if handle.id == 0 {
+		return "", ErrKeyReleased
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// find out exactly what the error was and set err
+			switch x := r.(type) {
+			case string:
+				err = errors.New(x)
+			case error:
+				err = x
+			default:
+				err = errors.New("unknown panic")
+			}
+			signature = ""
+		}
+	}()
+
+	if err := s.resetArena(len(message)); err != nil {
+		log.Println("arena error:", err)
+		return "", errors.New("failed to allocate memory for message")
+	}
+
+	// write message to wasm memory
+	messagePtr, err := s.putBytes(message)
+	if err != nil {
+		return "", errors.New("failed to write message to memory for signing")
+	}
+
+	signaturePtr, err := s.call(s.signWithKey, uint64(handle.id), messagePtr, uint64(len(message)), opts.flags())
+	if err != nil {
+		log.Println("sign_with_key error:", err)
+		return "", errors.New("failed to sign message")
+	}
+	if signaturePtr == 0 {
+		return "", errors.New("internal error when signing message")
+	}
+
+	// read signature string from memory
+	signature, ok := s.readResultString(uint32(signaturePtr), SIGNATURE_SIZE)
+	if !ok {
+		return "", errors.New("failed to sign message")
+	}
+
+	return signature, nil
+}
@@ -0,0 +1,117 @@
+package literal
+
+import (
+	"fmt"
+
+	"github.com/zkportal/aleo-utils-go/codec"
+)
+
+// Address is an Aleo address, stored as the little-endian bytes of the x-coordinate of the address point.
+type Address [codec.AddressSize]byte
+
+// NewAddress creates an address from the group element of the address point.
+func NewAddress(g Group) Address {
+	return Address(g)
+}
+
+// ParseAddress parses an `aleo1...` address literal, checking that it's a point of the prime-order subgroup.
+func ParseAddress(str string) (Address, error) {
+	x, err := codec.DecodeAddress(str)
+	if err != nil {
+		return Address{}, err
+	}
+
+	g, err := GroupFromBytesLE(x)
+	if err != nil {
+		return Address{}, fmt.Errorf("%w: %w", codec.ErrInvalidAddress, err)
+	}
+
+	return Address(g), nil
+}
+
+// Group returns the address point.
+func (a Address) Group() Group {
+	return Group(a)
+}
+
+// BytesLE returns the little-endian bytes of the x-coordinate of the address point.
+func (a Address) BytesLE() []byte {
+	return append([]byte(nil), a[:]...)
+}
+
+// String formats the address as an `aleo1...` literal.
+func (a Address) String() string {
+	return codec.Bech32mEncode(codec.AddressPrefix, a[:])
+}
+
+// Signature is an Aleo Schnorr signature: the challenge and the response scalars, and the compute key, which
+// consists of the public key and the public randomizer of the signer.
+type Signature struct {
+	Challenge Scalar
+	Response  Scalar
+	// PkSig is the public key of the signer
+	PkSig Group
+	// PrSig is the public randomizer of the signer
+	PrSig Group
+}
+
+// SignatureFromBytes decodes a signature from its snarkVM byte format: challenge, response and compute key,
+// checking every component.
+func SignatureFromBytes(buf []byte) (Signature, error) {
+	if len(buf) != codec.SignatureSize {
+		return Signature{}, fmt.Errorf("%w: signature needs %d bytes, got %d", ErrInvalidLiteral, codec.SignatureSize, len(buf))
+	}
+
+	challenge, err := ScalarFromBytesLE(buf[0:FieldSize])
+	if err != nil {
+		return Signature{}, fmt.Errorf("challenge: %w", err)
+	}
+
+	response, err := ScalarFromBytesLE(buf[FieldSize : 2*FieldSize])
+	if err != nil {
+		return Signature{}, fmt.Errorf("response: %w", err)
+	}
+
+	pkSig, err := GroupFromBytesLE(buf[2*FieldSize : 3*FieldSize])
+	if err != nil {
+		return Signature{}, fmt.Errorf("public key: %w", err)
+	}
+
+	prSig, err := GroupFromBytesLE(buf[3*FieldSize:])
+	if err != nil {
+		return Signature{}, fmt.Errorf("public randomizer: %w", err)
+	}
+
+	return Signature{
+		Challenge: challenge,
+		Response:  response,
+		PkSig:     pkSig,
+		PrSig:     prSig,
+	}, nil
+}
+
+// ParseSignature parses a `sign1...` signature literal, checking every component.
+func ParseSignature(str string) (Signature, error) {
+	payload, err := codec.DecodeBech32m(str, codec.SignaturePrefix, codec.SignatureSize)
+	if err != nil {
+		return Signature{}, fmt.Errorf("%w: %w", ErrInvalidLiteral, err)
+	}
+
+	return SignatureFromBytes(payload)
+}
+
+// Bytes returns the signature in the snarkVM byte format: challenge, response and compute key.
+func (s Signature) Bytes() []byte {
+	buf := make([]byte, 0, codec.SignatureSize)
+	buf = append(buf, s.Challenge[:]...)
+	buf = append(buf, s.Response[:]...)
+	buf = append(buf, s.PkSig[:]...)
+	buf = append(buf, s.PrSig[:]...)
+
+	return buf
+}
+
+// String formats the signature as a `sign1...` literal.
+func (s Signature) String() string {
+	return codec.Bech32mEncode(codec.SignaturePrefix, s.Bytes())
+}
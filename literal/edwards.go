@@ -0,0 +1,196 @@
+package literal
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/zkportal/aleo-utils-go/field"
+)
+
+// Arithmetic of the twisted Edwards curve over the Aleo base field, which Aleo uses for keys and signatures:
+// -x^2 + y^2 = 1 + d*x^2*y^2. It's used only with public data, so it's not constant-time.
+
+var (
+	edwardsD = new(field.Base).SetUint64(3021)
+	// order of the prime-order subgroup as a little-endian integer
+	subgroupOrder = reverse(ScalarModulus.Bytes())
+)
+
+// edwardsPoint is a point in projective coordinates (X:Y:Z), x = X/Z, y = Y/Z
+type edwardsPoint struct {
+	x, y, z field.Base
+}
+
+func edwardsIdentity() edwardsPoint {
+	var p edwardsPoint
+	p.y.One()
+	p.z.One()
+
+	return p
+}
+
+func (p edwardsPoint) isIdentity() bool {
+	return p.x.IsZero() == 1 && p.y.Equal(&p.z) == 1
+}
+
+// add returns p + q, using the add-2008-bbjlp formulas with a = -1
+func (p edwardsPoint) add(q edwardsPoint) edwardsPoint {
+	var a, b, c, d, e, f, g, t, u field.Base
+
+	a.Mul(&p.z, &q.z)
+	b.Square(&a)
+	c.Mul(&p.x, &q.x)
+	d.Mul(&p.y, &q.y)
+	e.Mul(edwardsD, e.Mul(&c, &d))
+	f.Sub(&b, &e)
+	g.Add(&b, &e)
+
+	t.Add(&p.x, &p.y)
+	u.Add(&q.x, &q.y)
+	t.Mul(&t, &u)
+	t.Sub(&t, &c)
+	t.Sub(&t, &d)
+
+	// y3 = A*G*(D - a*C) = A*G*(D + C)
+	var r edwardsPoint
+	r.x.Mul(r.x.Mul(&a, &f), &t)
+	r.y.Mul(r.y.Mul(&a, &g), u.Add(&d, &c))
+	r.z.Mul(&f, &g)
+
+	return r
+}
+
+// mul returns [k]p using double-and-add, k is a little-endian integer
+func (p edwardsPoint) mul(k []byte) edwardsPoint {
+	result := edwardsIdentity()
+	for i := 8*len(k) - 1; i >= 0; i-- {
+		result = result.add(result)
+		if k[i/8]>>(i%8)&1 == 1 {
+			result = result.add(p)
+		}
+	}
+
+	return result
+}
+
+// affineX returns the x-coordinate of the point
+func (p edwardsPoint) affineX() field.Base {
+	var x, zInv field.Base
+	x.Mul(&p.x, zInv.Inverse(&p.z))
+
+	return x
+}
+
+// edwardsPointFromX recovers a point of the prime-order subgroup from its x-coordinate, the same way snarkVM's
+// Group::from_x_coordinate does. Returns false if there's no such point.
+func edwardsPointFromX(x *field.Base) (edwardsPoint, bool) {
+	// y^2 = (1 + x^2) / (1 - d*x^2)
+	var x2, num, den, y2 field.Base
+	x2.Square(x)
+	num.Add(new(field.Base).One(), &x2)
+	den.Sub(new(field.Base).One(), den.Mul(edwardsD, &x2))
+	if den.IsZero() == 1 {
+		return edwardsPoint{}, false
+	}
+	y2.Mul(&num, den.Inverse(&den))
+
+	y, ok := sqrt(&y2)
+	if !ok {
+		return edwardsPoint{}, false
+	}
+
+	// only one of (x, y) and (x, -y) can be in the prime-order subgroup, as they differ by a point of order 2
+	for _, candidate := range []field.Base{y, *new(field.Base).Neg(&y)} {
+		p := edwardsPoint{x: *x, y: candidate}
+		p.z.One()
+		if p.mul(subgroupOrder).isIdentity() {
+			return p, true
+		}
+	}
+
+	return edwardsPoint{}, false
+}
+
+// sqrt returns a square root of a field element, or false if it's not a square
+func sqrt(a *field.Base) (field.Base, bool) {
+	root := new(big.Int).ModSqrt(leBytesToInt(a.BytesLE()), FieldModulus)
+	if root == nil {
+		return field.Base{}, false
+	}
+
+	var buf [FieldSize]byte
+	root.FillBytes(buf[:])
+
+	var y field.Base
+	if _, err := y.SetBytesLE(reverse(buf[:])); err != nil {
+		return field.Base{}, false
+	}
+
+	return y, true
+}
+
+// leBytesToInt converts little-endian bytes to an integer
+func leBytesToInt(buf []byte) *big.Int {
+	be := make([]byte, len(buf))
+	for i, b := range buf {
+		be[len(buf)-1-i] = b
+	}
+
+	return new(big.Int).SetBytes(be)
+}
+
+// generatorX is the x-coordinate of the generator which snarkVM uses for account keys and signatures. It's derived
+// by hashing the domain `AleoAccountEncryptionAndSignatureScheme0` to the curve, so it differs from the curve
+// generator.
+const generatorX = "522678458525321116977504528531602186870683848189190546523208313015552693483"
+
+var generator = func() Group {
+	x, ok := new(big.Int).SetString(generatorX, 10)
+	if !ok {
+		panic("literal: invalid generator")
+	}
+
+	g, err := NewGroup(x)
+	if err != nil {
+		panic(err)
+	}
+
+	return g
+}()
+
+// point returns the subgroup point of the group element, which is unique for its x-coordinate
+func (g Group) point() edwardsPoint {
+	var x field.Base
+	if _, err := x.SetBytesLE(g[:]); err != nil {
+		panic(fmt.Sprintf("literal: invalid group element: %v", err))
+	}
+
+	p, ok := edwardsPointFromX(&x)
+	if !ok {
+		panic("literal: group element is not in the prime-order subgroup")
+	}
+
+	return p
+}
+
+func groupFromPoint(p edwardsPoint) Group {
+	x := p.affineX()
+
+	return Group(x.BytesLE())
+}
+
+// Generator returns the generator of the prime-order subgroup which Aleo uses to derive keys, addresses and
+// signatures.
+func Generator() Group {
+	return generator
+}
+
+// Add returns g + h. It panics if either of the group elements wasn't created by this package.
+func (g Group) Add(h Group) Group {
+	return groupFromPoint(g.point().add(h.point()))
+}
+
+// Mul returns [s]g. It panics if the group element wasn't created by this package.
+func (g Group) Mul(s Scalar) Group {
+	return groupFromPoint(g.point().mul(s[:]))
+}
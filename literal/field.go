@@ -0,0 +1,246 @@
+package literal
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/zkportal/aleo-utils-go/field"
+)
+
+// FieldSize is the size of a field element, a scalar or a group x-coordinate in bytes
+const FieldSize = 32
+
+var (
+	// FieldModulus is the modulus of the Aleo base field, which is the scalar field of BLS12-377
+	FieldModulus = field.BaseModulus()
+	// ScalarModulus is the modulus of the Aleo scalar field, which is the order of the prime-order subgroup
+	// of the Aleo Edwards curve
+	ScalarModulus = field.ScalarModulus()
+)
+
+// Field is an element of the Aleo base field as little-endian bytes, the `field` Leo type.
+type Field [FieldSize]byte
+
+// Scalar is an element of the Aleo scalar field as little-endian bytes, the `scalar` Leo type.
+type Scalar [FieldSize]byte
+
+// Group is a point of the prime-order subgroup of the Aleo Edwards curve, the `group` Leo type. It's stored as
+// the little-endian bytes of its x-coordinate, which is also the group literal value.
+type Group [FieldSize]byte
+
+// canonical checks that little-endian bytes are a canonical element of a field
+type canonical func(buf []byte) error
+
+func baseCanonical(buf []byte) error {
+	_, err := new(field.Base).SetBytesLE(buf)
+	return err
+}
+
+func scalarCanonical(buf []byte) error {
+	_, err := new(field.Scalar).SetBytesLE(buf)
+	return err
+}
+
+// toFieldBytes converts a value to little-endian bytes, checking that it's an element of the field
+func toFieldBytes(value *big.Int, check canonical, name string) ([FieldSize]byte, error) {
+	var buf [FieldSize]byte
+	if value.Sign() < 0 || value.BitLen() > 8*FieldSize {
+		return buf, fmt.Errorf("%w: %s is not in the %s range", ErrOutOfRange, value, name)
+	}
+
+	value.FillBytes(buf[:])
+	copy(buf[:], reverse(buf[:]))
+
+	return checkFieldBytes(buf, check, name)
+}
+
+// checkFieldBytes checks that little-endian bytes are an element of the field
+func checkFieldBytes(buf [FieldSize]byte, check canonical, name string) ([FieldSize]byte, error) {
+	if err := check(buf[:]); err != nil {
+		if errors.Is(err, field.ErrNotCanonical) {
+			return [FieldSize]byte{}, fmt.Errorf("%w: %s is not in the %s range", ErrOutOfRange, leBytesToInt(buf[:]), name)
+		}
+		return [FieldSize]byte{}, fmt.Errorf("%w: %w", ErrInvalidLiteral, err)
+	}
+
+	return buf, nil
+}
+
+// fromBytesLE copies little-endian bytes, checking the size and that the value is an element of the field
+func fromBytesLE(buf []byte, check canonical, name string) ([FieldSize]byte, error) {
+	if len(buf) != FieldSize {
+		return [FieldSize]byte{}, fmt.Errorf("%w: %s needs %d bytes, got %d", ErrInvalidLiteral, name, FieldSize, len(buf))
+	}
+
+	return checkFieldBytes([FieldSize]byte(buf), check, name)
+}
+
+// parseFieldLiteral parses a non-negative decimal literal with the suffix
+func parseFieldLiteral(str string, suffix string, check canonical) ([FieldSize]byte, error) {
+	negative, digits, gotSuffix, err := splitLiteral(str)
+	if err != nil {
+		return [FieldSize]byte{}, err
+	}
+	if gotSuffix != suffix {
+		return [FieldSize]byte{}, fmt.Errorf("%w: %q is not a %s", ErrInvalidLiteral, str, suffix)
+	}
+	if negative {
+		return [FieldSize]byte{}, fmt.Errorf("%w: negative %s literals are not supported", ErrInvalidLiteral, suffix)
+	}
+
+	value, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return [FieldSize]byte{}, fmt.Errorf("%w: %q", ErrInvalidLiteral, str)
+	}
+
+	return toFieldBytes(value, check, suffix)
+}
+
+// NewField creates a field element, the value must be less than FieldModulus.
+func NewField(value *big.Int) (Field, error) {
+	return toFieldBytes(value, baseCanonical, "field")
+}
+
+// FieldFromBytesLE creates a field element from its 32 little-endian bytes.
+func FieldFromBytesLE(buf []byte) (Field, error) {
+	return fromBytesLE(buf, baseCanonical, "field")
+}
+
+// FieldFromBytesBE creates a field element from its 32 big-endian bytes.
+func FieldFromBytesBE(buf []byte) (Field, error) {
+	return FieldFromBytesLE(reverse(buf))
+}
+
+// ParseField parses a field literal, e.g. `123field`.
+func ParseField(str string) (Field, error) {
+	return parseFieldLiteral(str, "field", baseCanonical)
+}
+
+// Big returns the field element value.
+func (f Field) Big() *big.Int {
+	return leBytesToInt(f[:])
+}
+
+// BytesLE returns the little-endian bytes of the field element.
+func (f Field) BytesLE() []byte {
+	return append([]byte(nil), f[:]...)
+}
+
+// BytesBE returns the big-endian bytes of the field element.
+func (f Field) BytesBE() []byte {
+	return reverse(f[:])
+}
+
+// String formats the field element as a Leo literal, e.g. `123field`.
+func (f Field) String() string {
+	return f.Big().String() + "field"
+}
+
+// NewScalar creates a scalar, the value must be less than ScalarModulus.
+func NewScalar(value *big.Int) (Scalar, error) {
+	return toFieldBytes(value, scalarCanonical, "scalar")
+}
+
+// ScalarFromBytesLE creates a scalar from its 32 little-endian bytes.
+func ScalarFromBytesLE(buf []byte) (Scalar, error) {
+	return fromBytesLE(buf, scalarCanonical, "scalar")
+}
+
+// ScalarFromBytesBE creates a scalar from its 32 big-endian bytes.
+func ScalarFromBytesBE(buf []byte) (Scalar, error) {
+	return ScalarFromBytesLE(reverse(buf))
+}
+
+// ParseScalar parses a scalar literal, e.g. `123scalar`.
+func ParseScalar(str string) (Scalar, error) {
+	return parseFieldLiteral(str, "scalar", scalarCanonical)
+}
+
+// Big returns the scalar value.
+func (s Scalar) Big() *big.Int {
+	return leBytesToInt(s[:])
+}
+
+// BytesLE returns the little-endian bytes of the scalar.
+func (s Scalar) BytesLE() []byte {
+	return append([]byte(nil), s[:]...)
+}
+
+// BytesBE returns the big-endian bytes of the scalar.
+func (s Scalar) BytesBE() []byte {
+	return reverse(s[:])
+}
+
+// String formats the scalar as a Leo literal, e.g. `123scalar`.
+func (s Scalar) String() string {
+	return s.Big().String() + "scalar"
+}
+
+// checkGroup checks that the x-coordinate belongs to a point of the prime-order subgroup
+func checkGroup(x [FieldSize]byte) (Group, error) {
+	var xf field.Base
+	if _, err := xf.SetBytesLE(x[:]); err != nil {
+		return Group{}, fmt.Errorf("%w: %w", ErrOutOfRange, err)
+	}
+	if _, ok := edwardsPointFromX(&xf); !ok {
+		return Group{}, fmt.Errorf("%w: %s is not an x-coordinate of a subgroup point", ErrOutOfRange, leBytesToInt(x[:]))
+	}
+
+	return Group(x), nil
+}
+
+// NewGroup creates a group element from the x-coordinate of a point of the prime-order subgroup.
+func NewGroup(x *big.Int) (Group, error) {
+	buf, err := toFieldBytes(x, baseCanonical, "group")
+	if err != nil {
+		return Group{}, err
+	}
+
+	return checkGroup(buf)
+}
+
+// GroupFromBytesLE creates a group element from the 32 little-endian bytes of its x-coordinate.
+func GroupFromBytesLE(buf []byte) (Group, error) {
+	x, err := fromBytesLE(buf, baseCanonical, "group")
+	if err != nil {
+		return Group{}, err
+	}
+
+	return checkGroup(x)
+}
+
+// GroupFromBytesBE creates a group element from the 32 big-endian bytes of its x-coordinate.
+func GroupFromBytesBE(buf []byte) (Group, error) {
+	return GroupFromBytesLE(reverse(buf))
+}
+
+// ParseGroup parses a group literal, which is the x-coordinate of the point, e.g. `0group`.
+func ParseGroup(str string) (Group, error) {
+	x, err := parseFieldLiteral(str, "group", baseCanonical)
+	if err != nil {
+		return Group{}, err
+	}
+
+	return checkGroup(x)
+}
+
+// X returns the x-coordinate of the group element.
+func (g Group) X() *big.Int {
+	return leBytesToInt(g[:])
+}
+
+// BytesLE returns the little-endian bytes of the x-coordinate.
+func (g Group) BytesLE() []byte {
+	return append([]byte(nil), g[:]...)
+}
+
+// BytesBE returns the big-endian bytes of the x-coordinate.
+func (g Group) BytesBE() []byte {
+	return reverse(g[:])
+}
+
+// String formats the group element as a Leo literal, e.g. `0group`.
+func (g Group) String() string {
+	return g.X().String() + "group"
+}
@@ -0,0 +1,309 @@
+package literal
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+// Type is a Leo integer type.
+type Type uint8
+
+const (
+	U8 Type = iota + 1
+	U16
+	U32
+	U64
+	U128
+	I8
+	I16
+	I32
+	I64
+	I128
+)
+
+var typeNames = map[Type]string{
+	U8:   "u8",
+	U16:  "u16",
+	U32:  "u32",
+	U64:  "u64",
+	U128: "u128",
+	I8:   "i8",
+	I16:  "i16",
+	I32:  "i32",
+	I64:  "i64",
+	I128: "i128",
+}
+
+// String returns the type name, which is the suffix of its literals, e.g. `u128`.
+func (t Type) String() string {
+	name, ok := typeNames[t]
+	if !ok {
+		return fmt.Sprintf("Type(%d)", t)
+	}
+
+	return name
+}
+
+// Bits returns the size of the type in bits.
+func (t Type) Bits() int {
+	switch t {
+	case U8, I8:
+		return 8
+	case U16, I16:
+		return 16
+	case U32, I32:
+		return 32
+	case U64, I64:
+		return 64
+	case U128, I128:
+		return 128
+	default:
+		return 0
+	}
+}
+
+// Size returns the size of the type in bytes.
+func (t Type) Size() int {
+	return t.Bits() / 8
+}
+
+// Signed checks if the type is a signed integer type.
+func (t Type) Signed() bool {
+	return t >= I8 && t <= I128
+}
+
+// Min returns the minimal value of the type.
+func (t Type) Min() *big.Int {
+	if !t.Signed() {
+		return new(big.Int)
+	}
+
+	return new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), uint(t.Bits()-1)))
+}
+
+// Max returns the maximal value of the type.
+func (t Type) Max() *big.Int {
+	bits := t.Bits()
+	if t.Signed() {
+		bits--
+	}
+
+	max := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	return max.Sub(max, big.NewInt(1))
+}
+
+func (t Type) valid() bool {
+	_, ok := typeNames[t]
+	return ok
+}
+
+// ParseType parses an integer type name, e.g. `u128`.
+func ParseType(name string) (Type, error) {
+	for t, typeName := range typeNames {
+		if typeName == name {
+			return t, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: unknown integer type %q", ErrInvalidLiteral, name)
+}
+
+// maximal size of an integer in bytes
+const maxIntegerSize = 16
+
+// Integer is a value of a Leo integer type. Integers are comparable, two integers are equal if they have the same
+// type and value. The zero value is not a valid integer, use the constructors.
+type Integer struct {
+	typ Type
+	// little-endian two's complement bytes, the unused bytes are zero
+	le [maxIntegerSize]byte
+}
+
+// NewInteger creates an integer of the type. Returns ErrOutOfRange if the value doesn't fit the type.
+func NewInteger(t Type, value *big.Int) (Integer, error) {
+	if !t.valid() {
+		return Integer{}, fmt.Errorf("%w: unknown integer type %d", ErrInvalidLiteral, t)
+	}
+
+	if value.Cmp(t.Min()) < 0 || value.Cmp(t.Max()) > 0 {
+		return Integer{}, fmt.Errorf("%w: %s doesn't fit %s", ErrOutOfRange, value, t)
+	}
+
+	// two's complement of negative values
+	v := value
+	if v.Sign() < 0 {
+		v = new(big.Int).Add(v, new(big.Int).Lsh(big.NewInt(1), uint(t.Bits())))
+	}
+
+	i := Integer{typ: t}
+	v.FillBytes(i.le[maxIntegerSize-t.Size():])
+	copy(i.le[:], reverse(i.le[:]))
+
+	return i, nil
+}
+
+func newUnsigned(t Type, v uint64) Integer {
+	i := Integer{typ: t}
+	binary.LittleEndian.PutUint64(i.le[:], v)
+
+	return i
+}
+
+func newSigned(t Type, v int64) Integer {
+	i := Integer{typ: t}
+	binary.LittleEndian.PutUint64(i.le[:], uint64(v))
+	// drop the sign extension beyond the type size
+	clear(i.le[t.Size():])
+
+	return i
+}
+
+// NewU8 creates a u8 integer.
+func NewU8(v uint8) Integer { return newUnsigned(U8, uint64(v)) }
+
+// NewU16 creates a u16 integer.
+func NewU16(v uint16) Integer { return newUnsigned(U16, uint64(v)) }
+
+// NewU32 creates a u32 integer.
+func NewU32(v uint32) Integer { return newUnsigned(U32, uint64(v)) }
+
+// NewU64 creates a u64 integer.
+func NewU64(v uint64) Integer { return newUnsigned(U64, v) }
+
+// NewI8 creates an i8 integer.
+func NewI8(v int8) Integer { return newSigned(I8, int64(v)) }
+
+// NewI16 creates an i16 integer.
+func NewI16(v int16) Integer { return newSigned(I16, int64(v)) }
+
+// NewI32 creates an i32 integer.
+func NewI32(v int32) Integer { return newSigned(I32, int64(v)) }
+
+// NewI64 creates an i64 integer.
+func NewI64(v int64) Integer { return newSigned(I64, v) }
+
+// NewU128 creates a u128 integer from its high and low 64 bits.
+func NewU128(hi uint64, lo uint64) Integer {
+	i := newUnsigned(U128, lo)
+	binary.LittleEndian.PutUint64(i.le[8:], hi)
+
+	return i
+}
+
+// IntegerFromBytesLE creates an integer from its little-endian two's complement bytes, e.g. a u128 from the
+// result of Session.HashMessage. The buffer size must match the type size.
+func IntegerFromBytesLE(t Type, buf []byte) (Integer, error) {
+	if !t.valid() {
+		return Integer{}, fmt.Errorf("%w: unknown integer type %d", ErrInvalidLiteral, t)
+	}
+
+	if len(buf) != t.Size() {
+		return Integer{}, fmt.Errorf("%w: %s needs %d bytes, got %d", ErrInvalidLiteral, t, t.Size(), len(buf))
+	}
+
+	i := Integer{typ: t}
+	copy(i.le[:], buf)
+
+	return i, nil
+}
+
+// IntegerFromBytesBE is like IntegerFromBytesLE, but uses big-endian bytes.
+func IntegerFromBytesBE(t Type, buf []byte) (Integer, error) {
+	return IntegerFromBytesLE(t, reverse(buf))
+}
+
+// ParseInteger parses an integer literal with its type, e.g. `12345u128` or `-1_000i32`.
+func ParseInteger(str string) (Integer, error) {
+	negative, digits, suffix, err := splitLiteral(str)
+	if err != nil {
+		return Integer{}, err
+	}
+
+	t, err := ParseType(suffix)
+	if err != nil {
+		return Integer{}, err
+	}
+
+	value, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Integer{}, fmt.Errorf("%w: %q", ErrInvalidLiteral, str)
+	}
+	if negative {
+		if !t.Signed() {
+			return Integer{}, fmt.Errorf("%w: %s is negative", ErrOutOfRange, str)
+		}
+		value.Neg(value)
+	}
+
+	return NewInteger(t, value)
+}
+
+// ParseIntegerOfType is like ParseInteger, but also checks that the literal has the type.
+func ParseIntegerOfType(t Type, str string) (Integer, error) {
+	i, err := ParseInteger(str)
+	if err != nil {
+		return Integer{}, err
+	}
+
+	if i.typ != t {
+		return Integer{}, fmt.Errorf("%w: %q is not %s", ErrInvalidLiteral, str, t)
+	}
+
+	return i, nil
+}
+
+// Type returns the integer type.
+func (i Integer) Type() Type {
+	return i.typ
+}
+
+// Big returns the integer value.
+func (i Integer) Big() *big.Int {
+	size := i.typ.Size()
+	value := new(big.Int).SetBytes(reverse(i.le[:size]))
+
+	if i.typ.Signed() && size > 0 && i.le[size-1]&0x80 != 0 {
+		value.Sub(value, new(big.Int).Lsh(big.NewInt(1), uint(i.typ.Bits())))
+	}
+
+	return value
+}
+
+// Uint64 returns the integer value if it's not negative and fits uint64.
+func (i Integer) Uint64() (uint64, bool) {
+	v := i.Big()
+	if !v.IsUint64() {
+		return 0, false
+	}
+
+	return v.Uint64(), true
+}
+
+// Int64 returns the integer value if it fits int64.
+func (i Integer) Int64() (int64, bool) {
+	v := i.Big()
+	if !v.IsInt64() {
+		return 0, false
+	}
+
+	return v.Int64(), true
+}
+
+// BytesLE returns little-endian two's complement bytes of the integer, the size matches the type size.
+func (i Integer) BytesLE() []byte {
+	buf := make([]byte, i.typ.Size())
+	copy(buf, i.le[:])
+
+	return buf
+}
+
+// BytesBE returns big-endian two's complement bytes of the integer, the size matches the type size.
+func (i Integer) BytesBE() []byte {
+	return reverse(i.le[:i.typ.Size()])
+}
+
+// String formats the integer as a Leo literal, e.g. `12345u128`.
+func (i Integer) String() string {
+	return i.Big().String() + i.typ.String()
+}
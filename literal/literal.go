@@ -0,0 +1,64 @@
+// Package literal converts between Go values and Leo literals without the WASM module: integers of all Leo
+// integer types, bools, fields, scalars, groups, addresses and signatures. Parsers check that the values are in
+// range, and byte conversions use the little-endian snarkVM encoding unless stated otherwise.
+package literal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidLiteral = errors.New("invalid literal")
+	ErrOutOfRange     = errors.New("literal value is out of range")
+)
+
+// reverse returns a reversed copy of a buffer, converting little-endian bytes to big-endian and vice versa
+func reverse(buf []byte) []byte {
+	reversed := make([]byte, len(buf))
+	for i, b := range buf {
+		reversed[len(buf)-1-i] = b
+	}
+
+	return reversed
+}
+
+// splitLiteral splits a numeric literal into its sign, decimal digits and type suffix, e.g. `-1_000i32` into
+// `-`, `1000` and `i32`. The digits can be separated with underscores, as in Leo.
+func splitLiteral(str string) (negative bool, digits string, suffix string, err error) {
+	rest, negative := strings.CutPrefix(str, "-")
+
+	end := 0
+	for end < len(rest) && (rest[end] >= '0' && rest[end] <= '9' || end > 0 && rest[end] == '_') {
+		end++
+	}
+	if end == 0 {
+		return false, "", "", fmt.Errorf("%w: %q has no digits", ErrInvalidLiteral, str)
+	}
+
+	digits = strings.ReplaceAll(rest[:end], "_", "")
+
+	return negative, digits, rest[end:], nil
+}
+
+// ParseBool parses a `true` or `false` literal.
+func ParseBool(str string) (bool, error) {
+	switch str {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	default:
+		return false, fmt.Errorf("%w: %q is not a bool", ErrInvalidLiteral, str)
+	}
+}
+
+// FormatBool formats a bool as a Leo literal.
+func FormatBool(b bool) string {
+	if b {
+		return "true"
+	}
+
+	return "false"
+}
@@ -0,0 +1,362 @@
+package literal
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/zkportal/aleo-utils-go/codec"
+	"github.com/zkportal/aleo-utils-go/field"
+)
+
+// the address is the same as in cmd/sgx
+const testAddress = "aleo1vz6e7yyv9anm7xpnl02nzwz5qdgvaypx428gpx5vdjnhgle64cpsld55le"
+
+func TestParseInteger(t *testing.T) {
+	tests := []struct {
+		str     string
+		typ     Type
+		value   string
+		le      []byte
+		wantStr string
+	}{
+		{str: "0u8", typ: U8, value: "0", le: []byte{0}},
+		{str: "255u8", typ: U8, value: "255", le: []byte{0xff}},
+		{str: "-128i8", typ: I8, value: "-128", le: []byte{0x80}},
+		{str: "-1i16", typ: I16, value: "-1", le: []byte{0xff, 0xff}},
+		{str: "1_000u32", typ: U32, value: "1000", le: []byte{0xe8, 0x03, 0, 0}, wantStr: "1000u32"},
+		{str: "18446744073709551615u64", typ: U64, value: "18446744073709551615", le: bytes.Repeat([]byte{0xff}, 8)},
+		{str: "-9223372036854775808i64", typ: I64, value: "-9223372036854775808", le: []byte{0, 0, 0, 0, 0, 0, 0, 0x80}},
+		{str: "340282366920938463463374607431768211455u128", typ: U128, value: "340282366920938463463374607431768211455", le: bytes.Repeat([]byte{0xff}, 16)},
+		{str: "-2i128", typ: I128, value: "-2", le: append([]byte{0xfe}, bytes.Repeat([]byte{0xff}, 15)...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			i, err := ParseInteger(tt.str)
+			if err != nil {
+				t.Fatalf("ParseInteger() error = %v", err)
+			}
+			if i.Type() != tt.typ || i.Big().String() != tt.value {
+				t.Errorf("ParseInteger() = %s %s, want %s %s", i.Big(), i.Type(), tt.value, tt.typ)
+			}
+			if !bytes.Equal(i.BytesLE(), tt.le) {
+				t.Errorf("Integer.BytesLE() = %x, want %x", i.BytesLE(), tt.le)
+			}
+
+			wantStr := tt.wantStr
+			if wantStr == "" {
+				wantStr = tt.str
+			}
+			if i.String() != wantStr {
+				t.Errorf("Integer.String() = %s, want %s", i.String(), wantStr)
+			}
+
+			fromLE, err := IntegerFromBytesLE(tt.typ, tt.le)
+			if err != nil || fromLE != i {
+				t.Errorf("IntegerFromBytesLE() = %s, %v, want %s", fromLE, err, i)
+			}
+
+			fromBE, err := IntegerFromBytesBE(tt.typ, i.BytesBE())
+			if err != nil || fromBE != i {
+				t.Errorf("IntegerFromBytesBE() = %s, %v, want %s", fromBE, err, i)
+			}
+
+			value, _ := new(big.Int).SetString(tt.value, 10)
+			fromBig, err := NewInteger(tt.typ, value)
+			if err != nil || fromBig != i {
+				t.Errorf("NewInteger() = %s, %v, want %s", fromBig, err, i)
+			}
+		})
+	}
+
+	invalid := []struct {
+		str     string
+		wantErr error
+	}{
+		{"", ErrInvalidLiteral},
+		{"u8", ErrInvalidLiteral},
+		{"1", ErrInvalidLiteral},
+		{"1u7", ErrInvalidLiteral},
+		{"1field", ErrInvalidLiteral},
+		{"_1u8", ErrInvalidLiteral},
+		{"1u8 ", ErrInvalidLiteral},
+		{"256u8", ErrOutOfRange},
+		{"128i8", ErrOutOfRange},
+		{"-129i8", ErrOutOfRange},
+		{"-1u64", ErrOutOfRange},
+		{"340282366920938463463374607431768211456u128", ErrOutOfRange},
+	}
+	for _, tt := range invalid {
+		if _, err := ParseInteger(tt.str); !errors.Is(err, tt.wantErr) {
+			t.Errorf("ParseInteger(%q) error = %v, want %v", tt.str, err, tt.wantErr)
+		}
+	}
+
+	if _, err := ParseIntegerOfType(U128, "1u64"); !errors.Is(err, ErrInvalidLiteral) {
+		t.Errorf("ParseIntegerOfType() error = %v, want %v", err, ErrInvalidLiteral)
+	}
+}
+
+func TestIntegerConstructors(t *testing.T) {
+	tests := []struct {
+		got  Integer
+		want string
+	}{
+		{NewU8(200), "200u8"},
+		{NewU16(65535), "65535u16"},
+		{NewU32(1 << 31), "2147483648u32"},
+		{NewU64(1 << 63), "9223372036854775808u64"},
+		{NewU128(1, 2), "18446744073709551618u128"},
+		{NewI8(-100), "-100i8"},
+		{NewI16(-32768), "-32768i16"},
+		{NewI32(-1), "-1i32"},
+		{NewI64(9223372036854775807), "9223372036854775807i64"},
+	}
+	for _, tt := range tests {
+		if tt.got.String() != tt.want {
+			t.Errorf("Integer.String() = %s, want %s", tt.got, tt.want)
+		}
+
+		parsed, err := ParseInteger(tt.want)
+		if err != nil || parsed != tt.got {
+			t.Errorf("ParseInteger(%q) = %s, %v, want equal integer", tt.want, parsed, err)
+		}
+	}
+
+	if v, ok := NewI32(-5).Int64(); !ok || v != -5 {
+		t.Errorf("Integer.Int64() = %d, %v, want -5", v, ok)
+	}
+	if _, ok := NewI32(-5).Uint64(); ok {
+		t.Error("Integer.Uint64() should fail with negative integer")
+	}
+	if _, ok := NewU128(1, 0).Uint64(); ok {
+		t.Error("Integer.Uint64() should fail with integer over 64 bits")
+	}
+
+	if _, err := NewInteger(U8, big.NewInt(-1)); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("NewInteger() error = %v, want %v", err, ErrOutOfRange)
+	}
+	if _, err := NewInteger(Type(0), big.NewInt(1)); err == nil {
+		t.Error("NewInteger() should fail with invalid type")
+	}
+	if _, err := IntegerFromBytesLE(U16, []byte{1}); err == nil {
+		t.Error("IntegerFromBytesLE() should fail with wrong size")
+	}
+}
+
+func TestFieldLiterals(t *testing.T) {
+	maxField := new(big.Int).Sub(FieldModulus, big.NewInt(1))
+	maxScalar := new(big.Int).Sub(ScalarModulus, big.NewInt(1))
+
+	field, err := ParseField(maxField.String() + "field")
+	if err != nil {
+		t.Fatalf("ParseField() error = %v", err)
+	}
+	if field.Big().Cmp(maxField) != 0 || field.String() != maxField.String()+"field" {
+		t.Errorf("ParseField() = %s, want %sfield", field, maxField)
+	}
+	if fromBE, err := FieldFromBytesBE(field.BytesBE()); err != nil || fromBE != field {
+		t.Errorf("FieldFromBytesBE() = %s, %v, want %s", fromBE, err, field)
+	}
+	if fromLE, err := FieldFromBytesLE(field.BytesLE()); err != nil || fromLE != field {
+		t.Errorf("FieldFromBytesLE() = %s, %v, want %s", fromLE, err, field)
+	}
+
+	one, err := ParseField("1field")
+	if err != nil {
+		t.Fatalf("ParseField() error = %v", err)
+	}
+	if want := append([]byte{1}, make([]byte, FieldSize-1)...); !bytes.Equal(one.BytesLE(), want) {
+		t.Errorf("Field.BytesLE() = %x, want %x", one.BytesLE(), want)
+	}
+
+	scalar, err := ParseScalar(maxScalar.String() + "scalar")
+	if err != nil {
+		t.Fatalf("ParseScalar() error = %v", err)
+	}
+	if scalar.Big().Cmp(maxScalar) != 0 {
+		t.Errorf("ParseScalar() = %s, want %sscalar", scalar, maxScalar)
+	}
+	if fromBE, err := ScalarFromBytesBE(scalar.BytesBE()); err != nil || fromBE != scalar {
+		t.Errorf("ScalarFromBytesBE() = %s, %v, want %s", fromBE, err, scalar)
+	}
+
+	invalid := []struct {
+		name    string
+		parse   func(string) error
+		str     string
+		wantErr error
+	}{
+		{"field modulus", parseErr(ParseField), FieldModulus.String() + "field", ErrOutOfRange},
+		{"scalar modulus", parseErr(ParseScalar), ScalarModulus.String() + "scalar", ErrOutOfRange},
+		{"field as scalar", parseErr(ParseScalar), "1field", ErrInvalidLiteral},
+		{"negative field", parseErr(ParseField), "-1field", ErrInvalidLiteral},
+		{"no digits", parseErr(ParseField), "field", ErrInvalidLiteral},
+		{"integer as field", parseErr(ParseField), "1u8", ErrInvalidLiteral},
+		{"not a group point", parseErr(ParseGroup), nonGroupX().String() + "group", ErrOutOfRange},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.parse(tt.str); !errors.Is(err, tt.wantErr) {
+				t.Errorf("parse(%q) error = %v, want %v", tt.str, err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := FieldFromBytesLE(make([]byte, FieldSize-1)); err == nil {
+		t.Error("FieldFromBytesLE() should fail with wrong size")
+	}
+	if _, err := ScalarFromBytesLE(bytes.Repeat([]byte{0xff}, FieldSize)); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("ScalarFromBytesLE() error = %v, want %v", err, ErrOutOfRange)
+	}
+}
+
+func parseErr[T any](parse func(string) (T, error)) func(string) error {
+	return func(str string) error {
+		_, err := parse(str)
+		return err
+	}
+}
+
+// nonGroupX returns an x-coordinate, which doesn't belong to a point of the prime-order subgroup
+func nonGroupX() *big.Int {
+	for x := uint64(2); ; x++ {
+		if _, ok := edwardsPointFromX(new(field.Base).SetUint64(x)); !ok {
+			return new(big.Int).SetUint64(x)
+		}
+	}
+}
+
+func TestGroupAndAddress(t *testing.T) {
+	identity, err := ParseGroup("0group")
+	if err != nil {
+		t.Fatalf("ParseGroup() error = %v", err)
+	}
+	if identity.String() != "0group" {
+		t.Errorf("Group.String() = %s, want 0group", identity)
+	}
+
+	address, err := ParseAddress(testAddress)
+	if err != nil {
+		t.Fatalf("ParseAddress() error = %v", err)
+	}
+	if address.String() != testAddress {
+		t.Errorf("Address.String() = %s, want %s", address, testAddress)
+	}
+
+	// the address is a group literal of its x-coordinate
+	group, err := ParseGroup(address.Group().String())
+	if err != nil {
+		t.Fatalf("ParseGroup() error = %v", err)
+	}
+	if NewAddress(group) != address {
+		t.Errorf("NewAddress() = %s, want %s", NewAddress(group), address)
+	}
+	if fromBE, err := GroupFromBytesBE(group.BytesBE()); err != nil || fromBE != group {
+		t.Errorf("GroupFromBytesBE() = %s, %v, want %s", fromBE, err, group)
+	}
+	if fromBig, err := NewGroup(group.X()); err != nil || fromBig != group {
+		t.Errorf("NewGroup() = %s, %v, want %s", fromBig, err, group)
+	}
+
+	var invalidX [FieldSize]byte
+	nonGroupX().FillBytes(invalidX[:])
+	invalidAddress := codec.Bech32mEncode(codec.AddressPrefix, reverse(invalidX[:]))
+	if _, err := ParseAddress(invalidAddress); !errors.Is(err, codec.ErrInvalidAddress) {
+		t.Errorf("ParseAddress() error = %v, want %v", err, codec.ErrInvalidAddress)
+	}
+}
+
+func TestGroupArithmetic(t *testing.T) {
+	g := Generator()
+	if g.String() != generatorX+"group" {
+		t.Errorf("Generator() = %s, want %sgroup", g, generatorX)
+	}
+
+	scalar := func(v int64) Scalar {
+		s, err := NewScalar(big.NewInt(v))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	var identity Group
+	if got := g.Mul(scalar(0)); got != identity {
+		t.Errorf("[0]G = %s, want 0group", got)
+	}
+	if got := g.Mul(scalar(1)); got != g {
+		t.Errorf("[1]G = %s, want %s", got, g)
+	}
+	if got := g.Add(identity); got != g {
+		t.Errorf("G + 0 = %s, want %s", got, g)
+	}
+	if got, want := g.Mul(scalar(2)), g.Add(g); got != want {
+		t.Errorf("[2]G = %s, want G + G = %s", got, want)
+	}
+	if got, want := g.Mul(scalar(5)), g.Mul(scalar(2)).Add(g.Mul(scalar(3))); got != want {
+		t.Errorf("[5]G = %s, want [2]G + [3]G = %s", got, want)
+	}
+
+	// [n - 1]G + G is the identity, n is the subgroup order
+	minusOne, err := NewScalar(new(big.Int).Sub(ScalarModulus, big.NewInt(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := g.Mul(minusOne).Add(g); got != identity {
+		t.Errorf("[n - 1]G + G = %s, want 0group", got)
+	}
+
+	// the results are valid group elements
+	if _, err := GroupFromBytesLE(g.Mul(scalar(7)).BytesLE()); err != nil {
+		t.Errorf("GroupFromBytesLE([7]G) error = %v", err)
+	}
+}
+
+func TestSignature(t *testing.T) {
+	address, err := ParseAddress(testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	challenge, _ := NewScalar(big.NewInt(1))
+	response, _ := NewScalar(big.NewInt(2))
+	sig := Signature{
+		Challenge: challenge,
+		Response:  response,
+		PkSig:     address.Group(),
+		PrSig:     address.Group(),
+	}
+
+	parsed, err := ParseSignature(sig.String())
+	if err != nil {
+		t.Fatalf("ParseSignature() error = %v", err)
+	}
+	if parsed != sig {
+		t.Errorf("ParseSignature() = %+v, want %+v", parsed, sig)
+	}
+
+	buf := sig.Bytes()
+	copy(buf[2*FieldSize:3*FieldSize], bytes.Repeat([]byte{0xff}, FieldSize))
+	if _, err := SignatureFromBytes(buf); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("SignatureFromBytes() error = %v, want %v", err, ErrOutOfRange)
+	}
+
+	if _, err := ParseSignature(testAddress); !errors.Is(err, ErrInvalidLiteral) {
+		t.Errorf("ParseSignature() error = %v, want %v", err, ErrInvalidLiteral)
+	}
+}
+
+func TestBool(t *testing.T) {
+	for _, b := range []bool{true, false} {
+		parsed, err := ParseBool(FormatBool(b))
+		if err != nil || parsed != b {
+			t.Errorf("ParseBool(FormatBool(%v)) = %v, %v", b, parsed, err)
+		}
+	}
+
+	if _, err := ParseBool("True"); !errors.Is(err, ErrInvalidLiteral) {
+		t.Errorf("ParseBool() error = %v, want %v", err, ErrInvalidLiteral)
+	}
+}
@@ -0,0 +1,230 @@
+package aleo_utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+var ErrModuleHashMismatch = errors.New("wrapper module hash doesn't match the pinned hash")
+
+// moduleExport describes a function, which the wrapper expects the WASM module to export
+type moduleExport struct {
+	params   []api.ValueType
+	results  []api.ValueType
+	optional bool
+}
+
+var i32, i64 = api.ValueTypeI32, api.ValueTypeI64
+
+// moduleExports lists the functions used by the wrapper with their signatures
+var moduleExports = map[string]moduleExport{
+	"new_private_key":               {params: nil, results: []api.ValueType{i32}},
+	"get_address":                   {params: []api.ValueType{i32, i32}, results: []api.ValueType{i32}},
+	"sign":                          {params: []api.ValueType{i32, i32, i32, i32}, results: []api.ValueType{i32}},
+	"alloc":                         {params: []api.ValueType{i32}, results: []api.ValueType{i32}},
+	"dealloc":                       {params: []api.ValueType{i32, i32}, results: nil},
+	"hash_message":                  {params: []api.ValueType{i32, i32}, results: []api.ValueType{i64}},
+	"hash_message_bytes":            {params: []api.ValueType{i32, i32}, results: []api.ValueType{i64}},
+	"format_message":                {params: []api.ValueType{i32, i32, i32}, results: []api.ValueType{i64}},
+	"formatted_message_to_bytes":    {params: []api.ValueType{i32, i32}, results: []api.ValueType{i64}},
+	"verify":                        {params: []api.ValueType{i32, i32, i32, i32, i32, i32}, results: []api.ValueType{i32}, optional: true},
+	"format_and_hash":               {params: []api.ValueType{i32, i32, i32}, results: []api.ValueType{i64}, optional: true},
+	"format_hash_sign":              {params: []api.ValueType{i32, i32, i32, i32, i32, i32}, results: []api.ValueType{i64}, optional: true},
+	"hash_message_batch":            {params: []api.ValueType{i32, i32}, results: []api.ValueType{i64}, optional: true},
+	"sign_batch":                    {params: []api.ValueType{i32, i32, i32, i32, i32}, results: []api.ValueType{i64}, optional: true},
+	"load_key":                      {params: []api.ValueType{i32, i32}, results: []api.ValueType{i32}, optional: true},
+	"release_key":                   {params: []api.ValueType{i32}, results: []api.ValueType{i32}, optional: true},
+	"release_all_keys":              {params: nil, results: nil, optional: true},
+	"sign_with_key":                 {params: []api.ValueType{i32, i32, i32, i32}, results: []api.ValueType{i32}, optional: true},
+	"sign_with_options":             {params: []api.ValueType{i32, i32, i32, i32, i32}, results: []api.ValueType{i32}, optional: true},
+	"decrypt_record":                {params: []api.ValueType{i32, i32, i32, i32}, results: []api.ValueType{i64}, optional: true},
+	"is_owner":                      {params: []api.ValueType{i32, i32, i32, i32}, results: []api.ValueType{i32}, optional: true},
+	"encrypt_record":                {params: []api.ValueType{i32, i32, i32, i32}, results: []api.ValueType{i64}, optional: true},
+	"record_commitment":             {params: []api.ValueType{i32, i32, i32, i32, i32, i32}, results: []api.ValueType{i64}, optional: true},
+	"record_serial_number":          {params: []api.ValueType{i32, i32, i32, i32}, results: []api.ValueType{i64}, optional: true},
+	"record_tag":                    {params: []api.ValueType{i32, i32, i32, i32}, results: []api.ValueType{i64}, optional: true},
+	"transition_view_key":           {params: []api.ValueType{i32, i32, i32, i32}, results: []api.ValueType{i64}, optional: true},
+	"decrypt_transition_ciphertext": {params: []api.ValueType{i32, i32, i32, i32, i32, i32, i32, i32, i32}, results: []api.ValueType{i64}, optional: true},
+	"program_address":               {params: []api.ValueType{i32, i32}, results: []api.ValueType{i64}, optional: true},
+	"mapping_key_id":                {params: []api.ValueType{i32, i32, i32, i32, i32, i32}, results: []api.ValueType{i64}, optional: true},
+	"plaintext_to_fields":           {params: []api.ValueType{i32, i32}, results: []api.ValueType{i64}, optional: true},
+	"sign_request":                  {params: []api.ValueType{i32, i32, i32, i32, i32, i32, i32, i32, i32, i32}, results: []api.ValueType{i64}, optional: true},
+	"authorize_fee_public":          {params: []api.ValueType{i32, i32, i32, i32, i64, i64}, results: []api.ValueType{i64}, optional: true},
+	"parse_transaction":             {params: []api.ValueType{i32, i32}, results: []api.ValueType{i64}, optional: true},
+	"check_transaction":             {params: []api.ValueType{i32, i32}, results: []api.ValueType{i64}, optional: true},
+	"version":                       {params: nil, results: []api.ValueType{i64}, optional: true},
+	"abi_version":                   {params: nil, results: []api.ValueType{i32}, optional: true},
+	"capabilities":                  {params: nil, results: []api.ValueType{i64}, optional: true},
+}
+
+// WithModuleBytes replaces the embedded WASM module with the provided one, e.g. a module built with a newer snarkVM.
+// The module must export all the functions used by the wrapper.
+func WithModuleBytes(wasm []byte) WrapperOption {
+	return func(o *wrapperOptions) {
+		o.module = wasm
+	}
+}
+
+// WithModuleFile is like WithModuleBytes, but reads the module from a file.
+func WithModuleFile(path string) WrapperOption {
+	return func(o *wrapperOptions) {
+		o.modulePath = path
+	}
+}
+
+// WithModuleSHA256 pins the WASM module to a hex-encoded SHA-256 hash. NewWrapper returns ErrModuleHashMismatch
+// if the module has a different hash.
+func WithModuleSHA256(hash string) WrapperOption {
+	return func(o *wrapperOptions) {
+		o.moduleHash = hash
+	}
+}
+
+// NewWrapperFromBytes creates a wrapper using the provided WASM module instead of the embedded one.
+func NewWrapperFromBytes(wasm []byte, opts ...WrapperOption) (wrapper Wrapper, closeFn func(), err error) {
+	return NewWrapper(append(opts, WithModuleBytes(wasm))...)
+}
+
+// NewWrapperFromFile creates a wrapper using the WASM module from a file instead of the embedded one.
+func NewWrapperFromFile(path string, opts ...WrapperOption) (wrapper Wrapper, closeFn func(), err error) {
+	return NewWrapper(append(opts, WithModuleFile(path))...)
+}
+
+// loadModule returns the WASM module selected by the options and checks it against the pinned hash.
+func loadModule(options *wrapperOptions) ([]byte, error) {
+	module := wasmBytes
+	if options.module != nil {
+		module = options.module
+	}
+	if options.modulePath != "" {
+		var err error
+		module, err = os.ReadFile(options.modulePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read wrapper module: %w", err)
+		}
+	}
+
+	if options.moduleHash != "" {
+		hash := sha256.Sum256(module)
+		if hex.EncodeToString(hash[:]) != options.moduleHash {
+			return nil, ErrModuleHashMismatch
+		}
+	}
+
+	return module, nil
+}
+
+// validateModule checks that the compiled module exports the functions used by the wrapper with correct signatures.
+func validateModule(cmod wazero.CompiledModule) error {
+	exported := cmod.ExportedFunctions()
+
+	for name, export := range moduleExports {
+		def, ok := exported[name]
+		if !ok {
+			if export.optional {
+				continue
+			}
+			return fmt.Errorf("wrapper module doesn't export function %s", name)
+		}
+
+		if !slices.Equal(def.ParamTypes(), export.params) || !slices.Equal(def.ResultTypes(), export.results) {
+			return fmt.Errorf("wrapper module function %s has unexpected signature", name)
+		}
+	}
+
+	if _, ok := cmod.ExportedMemories()["memory"]; !ok {
+		return errors.New("wrapper module doesn't export memory")
+	}
+
+	return nil
+}
+
+// moduleInfo is the information reported by the module itself
+type moduleInfo struct {
+	version      string
+	capabilities Capabilities
+}
+
+// probeModule instantiates the module to read its version and capabilities, and checks that the module ABI
+// is compatible with the wrapper.
+func probeModule(ctx context.Context, runtime wazero.Runtime, cmod wazero.CompiledModule, moduleConfig wazero.ModuleConfig) (*moduleInfo, error) {
+	mod, err := runtime.InstantiateModule(ctx, cmod, moduleConfig.WithName(""))
+	if err != nil {
+		return nil, fmt.Errorf("failed to instantiate wrapper module: %w", err)
+	}
+	defer mod.Close(ctx)
+
+	info := new(moduleInfo)
+
+	if mod.ExportedFunction("version") != nil {
+		version, err := callModuleString(ctx, mod, "version")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get wrapper module version: %w", err)
+		}
+		info.version = version
+	}
+
+	// modules without the handshake are treated as ABI version 0
+	if mod.ExportedFunction("abi_version") == nil {
+		info.capabilities = legacyCapabilities(cmod)
+		return info, nil
+	}
+
+	result, err := mod.ExportedFunction("abi_version").Call(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wrapper module ABI version: %w", err)
+	}
+
+	abiVersion := uint32(result[0])
+	if abiVersion < MIN_ABI_VERSION || abiVersion > MAX_ABI_VERSION {
+		return nil, fmt.Errorf("%w: module ABI version %d, supported %d-%d", ErrIncompatibleModule, abiVersion, MIN_ABI_VERSION, MAX_ABI_VERSION)
+	}
+
+	if mod.ExportedFunction("capabilities") == nil {
+		return nil, fmt.Errorf("%w: module doesn't report capabilities", ErrIncompatibleModule)
+	}
+
+	rawCapabilities, err := callModuleString(ctx, mod, "capabilities")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wrapper module capabilities: %w", err)
+	}
+
+	info.capabilities, err = parseCapabilities(rawCapabilities)
+	if err != nil {
+		return nil, err
+	}
+	info.capabilities.ABIVersion = abiVersion
+
+	return info, nil
+}
+
+// callModuleString calls a module function without arguments, which returns a string as a pointer and length.
+func callModuleString(ctx context.Context, mod api.Module, name string) (string, error) {
+	result, err := mod.ExportedFunction(name).Call(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// take the first (big endian) 32 bits as string size
+	strLen := uint32(result[0] >> 32)
+
+	// casting uint64 to uint32 discards the first (big endian) 32 bits so we're left with the last 32 bits of the result pointer
+	strPtr := uint32(result[0])
+
+	buf, ok := mod.Memory().Read(strPtr, strLen)
+	if !ok {
+		return "", errors.New("failed to read string from module memory")
+	}
+
+	// the module instance is closed after probing so there's no need to deallocate the string.
+	// explicit copy is not needed since we create a string, which copies the slice instead of referencing it
+	return string(buf), nil
+}
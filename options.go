@@ -0,0 +1,104 @@
+package aleo_utils
+
+import (
+	"io"
+	"sync"
+)
+
+// WrapperOption configures a Wrapper created with NewWrapper.
+type WrapperOption func(*wrapperOptions)
+
+type wrapperOptions struct {
+	compilationCacheDir string
+	runtimeMode         RuntimeMode
+	module              []byte
+	modulePath          string
+	moduleHash          string
+	signOptions         SignOptions
+	randSource          io.Reader
+}
+
+// RuntimeMode selects the engine, which executes the WASM module.
+type RuntimeMode int
+
+const (
+	// RuntimeModeAuto uses the compiler if it's supported on the current platform, otherwise the interpreter.
+	RuntimeModeAuto RuntimeMode = iota
+	// RuntimeModeCompiler compiles the WASM module to native code. Requires executable memory, which needs to be
+	// enabled explicitly in SGX enclaves.
+	RuntimeModeCompiler
+	// RuntimeModeInterpreter interprets the WASM module. It's slower than the compiler, but works on every platform
+	// and doesn't require executable memory.
+	RuntimeModeInterpreter
+)
+
+func (m RuntimeMode) String() string {
+	switch m {
+	case RuntimeModeAuto:
+		return "auto"
+	case RuntimeModeCompiler:
+		return "compiler"
+	case RuntimeModeInterpreter:
+		return "interpreter"
+	default:
+		return "unknown"
+	}
+}
+
+// WithCompilationCache enables a file-backed compilation cache in the specified directory. The compiled WASM
+// module is stored in a subdirectory keyed by the module hash and wazero version, so that the module doesn't
+// need to be compiled on every process start. A corrupted cache is discarded and the module is recompiled.
+func WithCompilationCache(dir string) WrapperOption {
+	return func(o *wrapperOptions) {
+		o.compilationCacheDir = dir
+	}
+}
+
+// WithRuntimeMode sets the engine used to run the WASM module, RuntimeModeAuto by default.
+func WithRuntimeMode(mode RuntimeMode) WrapperOption {
+	return func(o *wrapperOptions) {
+		o.runtimeMode = mode
+	}
+}
+
+// WithSignatureSelfCheck sets whether Sign verifies every signature it creates, enabled by default. Disabling
+// the self check roughly halves the cost of signing, use it only for trusted high-volume signers.
+func WithSignatureSelfCheck(enabled bool) WrapperOption {
+	return func(o *wrapperOptions) {
+		o.signOptions.SkipSelfCheck = !enabled
+	}
+}
+
+// WithDeterministicNonce sets whether Sign derives signature nonces from the private key and the message, disabled
+// by default. Deterministic signatures are reproducible, which is useful for golden files and deduplication.
+// Sign returns ErrNotSupported if the module doesn't support FeatureDeterministicNonce.
+func WithDeterministicNonce(enabled bool) WrapperOption {
+	return func(o *wrapperOptions) {
+		o.signOptions.DeterministicNonce = enabled
+	}
+}
+
+// WithRandSource sets the source of randomness used by the WASM module, crypto/rand.Reader by default. It's the only
+// source of entropy for NewPrivateKey and Sign, so a seeded reader makes keys and signatures reproducible, e.g.
+// in tests, and SGX builds can plug in hardware-backed entropy. The reader is shared by all sessions of the wrapper,
+// and reads from it are serialized.
+//
+// Never use a predictable reader in production, it makes private keys and signature nonces predictable.
+func WithRandSource(r io.Reader) WrapperOption {
+	return func(o *wrapperOptions) {
+		o.randSource = r
+	}
+}
+
+// lockedReader serializes reads from a reader shared by concurrently used sessions
+type lockedReader struct {
+	mu sync.Mutex
+	r  io.Reader
+}
+
+func (l *lockedReader) Read(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.r.Read(p)
+}
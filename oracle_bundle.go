@@ -0,0 +1,262 @@
+package aleo_utils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/zkportal/aleo-utils-go/literal"
+)
+
+// OracleBundle is a set of artifacts produced by an enclave oracle (see cmd/sgx), which can be verified offline
+// using VerifyOracleBundle.
+type OracleBundle struct {
+	// Address is the Aleo address of the key, which signed the report hash
+	Address string
+	// ExtractedData is the data, which the enclave attests to
+	ExtractedData []byte
+	// FormattedExtractedData is ExtractedData formatted with FormatMessage
+	FormattedExtractedData []byte
+	// ExtractedDataHash is a result of HashMessage of FormattedExtractedData, embedded into the report as user data
+	ExtractedDataHash []byte
+	// ExtractedDataHashField is a result of HashMessageToString of FormattedExtractedData
+	ExtractedDataHashField string
+	// Report is the enclave remote report
+	Report []byte
+	// FormattedReport is Report formatted with FormatMessage
+	FormattedReport []byte
+	// ReportHash is a result of HashMessageToString of FormattedReport
+	ReportHash string
+	// Signature is a signature of the little-endian bytes of ReportHash, created with Sign
+	Signature string
+}
+
+// ParseOracleBundle reads an oracle bundle in the `Key = "value"` format written by cmd/sgx. Unknown keys are ignored.
+func ParseOracleBundle(r io.Reader) (*OracleBundle, error) {
+	bundle := new(OracleBundle)
+
+	scanner := bufio.NewScanner(r)
+	// formatted messages are long single lines
+	scanner.Buffer(nil, 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		key, rawValue, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid bundle line %q", line)
+		}
+		key = strings.TrimSpace(key)
+
+		value, err := strconv.Unquote(strings.TrimSpace(rawValue))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for key %q: %w", key, err)
+		}
+
+		switch key {
+		case "Address":
+			bundle.Address = value
+		case "Extracted data":
+			bundle.ExtractedData, err = hex.DecodeString(value)
+		case "Formatted extracted data":
+			bundle.FormattedExtractedData = []byte(value)
+		case "Hashed extracted data":
+			bundle.ExtractedDataHash, err = hex.DecodeString(value)
+		case "Hashed extracted data as field":
+			bundle.ExtractedDataHashField = value
+		case "Report":
+			bundle.Report, err = hex.DecodeString(value)
+		case "Formatted report":
+			bundle.FormattedReport = []byte(value)
+		case "Hashed report":
+			bundle.ReportHash = value
+		case "Signature":
+			bundle.Signature = value
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for key %q: %w", key, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+// QuoteVerifier checks authenticity of an SGX quote, for example using Intel PCS collateral. Use EGo's
+// eclient.VerifyRemoteReport in production, or a local stand-in in tests.
+type QuoteVerifier interface {
+	VerifyQuote(report []byte) error
+}
+
+// QuoteVerifierFunc is an adapter to allow the use of ordinary functions as a QuoteVerifier.
+type QuoteVerifierFunc func(report []byte) error
+
+func (f QuoteVerifierFunc) VerifyQuote(report []byte) error {
+	return f(report)
+}
+
+// ReportPolicy describes the expected enclave identity. Empty fields are not checked.
+type ReportPolicy struct {
+	// UniqueID is the expected MRENCLAVE
+	UniqueID []byte
+	// SignerID is the expected MRSIGNER
+	SignerID []byte
+	// ProductID is the expected product ID in the 16-byte form used by EGo
+	ProductID []byte
+	// MinSecurityVersion is the minimal accepted ISV SVN
+	MinSecurityVersion uint16
+}
+
+var (
+	ErrUntrustedQuote  = errors.New("untrusted quote")
+	ErrBundlePolicy    = errors.New("report doesn't match the policy")
+	ErrBundleMismatch  = errors.New("bundle artifacts don't match")
+	ErrBundleSignature = errors.New("invalid bundle signature")
+)
+
+// VerifyOracleBundle checks an oracle bundle offline:
+//   - the report quote is checked by the quote verifier;
+//   - the report enclave identity is checked against the policy;
+//   - the report user data is checked to contain the extracted data hash;
+//   - formatting and hashing of the extracted data and the report are recomputed and compared with the bundle;
+//   - the report hash signature is checked against the bundle address.
+func VerifyOracleBundle(s Session, bundle *OracleBundle, policy ReportPolicy, quoteVerifier QuoteVerifier) (*SGXReport, error) {
+	if bundle == nil {
+		return nil, errors.New("bundle is nil")
+	}
+
+	if quoteVerifier == nil {
+		return nil, errors.New("quote verifier is required")
+	}
+
+	if err := quoteVerifier.VerifyQuote(bundle.Report); err != nil {
+		return nil, fmt.Errorf("quote verification failed: %w", err)
+	}
+
+	report, err := ParseSGXReport(bundle.Report)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkReportPolicy(report, policy); err != nil {
+		return nil, err
+	}
+
+	// the enclave embeds the extracted data hash into the report, the rest of the user data must be zeroes
+	if len(bundle.ExtractedDataHash) > len(report.Data) ||
+		!bytes.Equal(report.Data[:len(bundle.ExtractedDataHash)], bundle.ExtractedDataHash) ||
+		!isZero(report.Data[len(bundle.ExtractedDataHash):]) {
+		return nil, fmt.Errorf("%w: report data doesn't contain extracted data hash", ErrBundleMismatch)
+	}
+
+	if err := checkFormattedMessage(s, bundle.ExtractedData, bundle.FormattedExtractedData); err != nil {
+		return nil, fmt.Errorf("extracted data: %w", err)
+	}
+
+	dataHash, err := s.HashMessage(bundle.FormattedExtractedData)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(dataHash, bundle.ExtractedDataHash) {
+		return nil, fmt.Errorf("%w: extracted data hash", ErrBundleMismatch)
+	}
+
+	dataHashField, err := s.HashMessageToString(bundle.FormattedExtractedData)
+	if err != nil {
+		return nil, err
+	}
+	if dataHashField != bundle.ExtractedDataHashField {
+		return nil, fmt.Errorf("%w: extracted data hash field", ErrBundleMismatch)
+	}
+
+	if err := checkFormattedMessage(s, bundle.Report, bundle.FormattedReport); err != nil {
+		return nil, fmt.Errorf("report: %w", err)
+	}
+
+	reportHash, err := s.HashMessageToString(bundle.FormattedReport)
+	if err != nil {
+		return nil, err
+	}
+	if reportHash != bundle.ReportHash {
+		return nil, fmt.Errorf("%w: report hash", ErrBundleMismatch)
+	}
+
+	// cmd/sgx signs the report hash as a u128, the same bytes as returned by HashMessage
+	reportHashInt, err := literal.ParseIntegerOfType(literal.U128, bundle.ReportHash)
+	if err != nil {
+		return nil, fmt.Errorf("%w: report hash: %w", ErrBundleMismatch, err)
+	}
+
+	valid, err := s.Verify(bundle.Address, reportHashInt.BytesLE(), bundle.Signature)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, ErrBundleSignature
+	}
+
+	return report, nil
+}
+
+func checkReportPolicy(report *SGXReport, policy ReportPolicy) error {
+	if len(policy.UniqueID) != 0 && !bytes.Equal(policy.UniqueID, report.UniqueID[:]) {
+		return fmt.Errorf("%w: unique ID", ErrBundlePolicy)
+	}
+
+	if len(policy.SignerID) != 0 && !bytes.Equal(policy.SignerID, report.SignerID[:]) {
+		return fmt.Errorf("%w: signer ID", ErrBundlePolicy)
+	}
+
+	if len(policy.ProductID) != 0 && !bytes.Equal(policy.ProductID, report.ProductID[:]) {
+		return fmt.Errorf("%w: product ID", ErrBundlePolicy)
+	}
+
+	if report.SecurityVersion < policy.MinSecurityVersion {
+		return fmt.Errorf("%w: security version %d is lower than %d", ErrBundlePolicy, report.SecurityVersion, policy.MinSecurityVersion)
+	}
+
+	return nil
+}
+
+// checkFormattedMessage formats the message using the same number of chunks as the expected formatted message
+// and compares the results
+func checkFormattedMessage(s Session, message []byte, formattedMessage []byte) error {
+	recovered, err := s.RecoverMessage(formattedMessage)
+	if err != nil {
+		return err
+	}
+
+	targetChunks := len(recovered) / MESSAGE_FORMAT_BLOCK_SIZE
+
+	formatted, err := s.FormatMessage(message, targetChunks)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(formatted, formattedMessage) {
+		return fmt.Errorf("%w: formatted message", ErrBundleMismatch)
+	}
+
+	return nil
+}
+
+func isZero(buf []byte) bool {
+	for _, b := range buf {
+		if b != 0 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,223 @@
+package aleo_utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// buildTestReport creates a fake remote report with the given enclave identity and user data
+func buildTestReport(uniqueID, signerID []byte, productID, svn uint16, data []byte) []byte {
+	quote := make([]byte, sgxQuoteHeaderSize+sgxReportBodySize+64)
+	body := quote[sgxQuoteHeaderSize:]
+	copy(body[sgxBodyMrEnclaveOffset:], uniqueID)
+	copy(body[sgxBodyMrSignerOffset:], signerID)
+	binary.LittleEndian.PutUint16(body[sgxBodyProductIDOffset:], productID)
+	binary.LittleEndian.PutUint16(body[sgxBodySvnOffset:], svn)
+	copy(body[sgxBodyReportDataOffset:], data)
+
+	header := make([]byte, sgxReportHeaderSize)
+	binary.LittleEndian.PutUint32(header[0:], 1)
+	binary.LittleEndian.PutUint32(header[4:], sgxReportTypeRemote)
+	binary.LittleEndian.PutUint64(header[8:], uint64(len(quote)))
+
+	return append(header, quote...)
+}
+
+func TestParseSGXReport(t *testing.T) {
+	uniqueID := bytes.Repeat([]byte{1}, 32)
+	signerID := bytes.Repeat([]byte{2}, 32)
+	report := buildTestReport(uniqueID, signerID, 0x0102, 7, []byte("user data"))
+
+	parsed, err := ParseSGXReport(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(parsed.UniqueID[:], uniqueID) {
+		t.Errorf("UniqueID = %x, want %x", parsed.UniqueID, uniqueID)
+	}
+	if !bytes.Equal(parsed.SignerID[:], signerID) {
+		t.Errorf("SignerID = %x, want %x", parsed.SignerID, signerID)
+	}
+	if parsed.ProductID[0] != 0x02 || parsed.ProductID[1] != 0x01 {
+		t.Errorf("ProductID = %x, want 0201...", parsed.ProductID)
+	}
+	if parsed.SecurityVersion != 7 {
+		t.Errorf("SecurityVersion = %d, want 7", parsed.SecurityVersion)
+	}
+	if !bytes.HasPrefix(parsed.Data[:], []byte("user data")) {
+		t.Errorf("Data = %x, want user data prefix", parsed.Data)
+	}
+
+	if _, err := ParseSGXReport(report[:100]); err == nil {
+		t.Error("ParseSGXReport should fail on a truncated report")
+	}
+	if _, err := ParseSGXReport(report[:len(report)-1]); err == nil {
+		t.Error("ParseSGXReport should fail on size mismatch")
+	}
+}
+
+func TestVerifyOracleBundle(t *testing.T) {
+	wrapper, closeFn, err := NewWrapper()
+	if err != nil {
+		t.Fatalf("NewWrapper error = %v\n", err)
+	}
+	defer closeFn()
+
+	s, err := wrapper.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	privKey, address, err := s.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// reproduce cmd/sgx output using a fake report
+	extractedData := []byte("btc/usd = 1.0")
+	message, err := s.FormatMessage(extractedData, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := s.HashMessage(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashAsField, err := s.HashMessageToString(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uniqueID := bytes.Repeat([]byte{0xaa}, 32)
+	signerID := bytes.Repeat([]byte{0xbb}, 32)
+	report := buildTestReport(uniqueID, signerID, 1, 2, hash)
+
+	formattedReport, err := s.FormatMessage(report, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashedReport, err := s.HashMessageToString(formattedReport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashedReportBytes, err := s.HashMessage(formattedReport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature, err := s.Sign(privKey, hashedReportBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// older cmd/sgx builds signed the report hash string, which binds only its first 16 digits
+	stringSignature, err := s.Sign(privKey, []byte(hashedReport))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Address = \"%s\"\n", address))
+	b.WriteString(fmt.Sprintf("Extracted data = \"%s\"\n", hex.EncodeToString(extractedData)))
+	b.WriteString(fmt.Sprintf("Formatted extracted data = \"%s\"\n", string(message)))
+	b.WriteString(fmt.Sprintf("Hashed extracted data = \"%s\"\n", hex.EncodeToString(hash)))
+	b.WriteString(fmt.Sprintf("Hashed extracted data as field = \"%s\"\n", hashAsField))
+	b.WriteString(fmt.Sprintf("Report = \"%s\"\n", hex.EncodeToString(report)))
+	b.WriteString(fmt.Sprintf("Report TCBStatus = \"%d\"\n", 0))
+	b.WriteString(fmt.Sprintf("Formatted report = \"%s\"\n", string(formattedReport)))
+	b.WriteString(fmt.Sprintf("Hashed report = \"%s\"\n", hashedReport))
+	b.WriteString(fmt.Sprintf("Signature = \"%s\"\n", signature))
+
+	bundle, err := ParseOracleBundle(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// local stand-in for Intel PCS, trusts only the quote it has seen
+	trustedQuote := hex.EncodeToString(report)
+	quoteVerifier := QuoteVerifierFunc(func(report []byte) error {
+		if hex.EncodeToString(report) != trustedQuote {
+			return ErrUntrustedQuote
+		}
+		return nil
+	})
+
+	policy := ReportPolicy{
+		UniqueID:           uniqueID,
+		SignerID:           signerID,
+		ProductID:          append([]byte{1}, make([]byte, 15)...),
+		MinSecurityVersion: 2,
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(b *OracleBundle, p *ReportPolicy)
+		wantErr error
+	}{
+		{
+			name: "untrusted quote",
+			modify: func(b *OracleBundle, p *ReportPolicy) {
+				b.Report = buildTestReport(uniqueID, signerID, 1, 2, hash[:8])
+			},
+			wantErr: ErrUntrustedQuote,
+		},
+		{
+			name:    "wrong unique ID",
+			modify:  func(b *OracleBundle, p *ReportPolicy) { p.UniqueID = signerID },
+			wantErr: ErrBundlePolicy,
+		},
+		{
+			name:    "security version too low",
+			modify:  func(b *OracleBundle, p *ReportPolicy) { p.MinSecurityVersion = 3 },
+			wantErr: ErrBundlePolicy,
+		},
+		{
+			name:    "tampered extracted data",
+			modify:  func(b *OracleBundle, p *ReportPolicy) { b.ExtractedData = []byte("btc/usd = 2.0") },
+			wantErr: ErrBundleMismatch,
+		},
+		{
+			name:    "tampered report hash",
+			modify:  func(b *OracleBundle, p *ReportPolicy) { b.ReportHash = hashAsField },
+			wantErr: ErrBundleMismatch,
+		},
+		{
+			name: "wrong signer",
+			modify: func(b *OracleBundle, p *ReportPolicy) {
+				_, otherAddress, _ := s.NewPrivateKey()
+				b.Address = otherAddress
+			},
+			wantErr: ErrBundleSignature,
+		},
+		{
+			name:    "signature of report hash string",
+			modify:  func(b *OracleBundle, p *ReportPolicy) { b.Signature = stringSignature },
+			wantErr: ErrBundleSignature,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := *bundle
+			p := policy
+			tt.modify(&b, &p)
+
+			_, err := VerifyOracleBundle(s, &b, p, quoteVerifier)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyOracleBundle() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	verified, err := VerifyOracleBundle(s, bundle, policy, quoteVerifier)
+	if err != nil {
+		t.Fatalf("VerifyOracleBundle() error = %v", err)
+	}
+	if !bytes.Equal(verified.UniqueID[:], uniqueID) {
+		t.Errorf("VerifyOracleBundle() UniqueID = %x, want %x", verified.UniqueID, uniqueID)
+	}
+}
@@ -0,0 +1,107 @@
+package poseidon
+
+import (
+	"github.com/zkportal/aleo-utils-go/field"
+)
+
+// grainLFSR is the Grain LFSR, which generates the round constants and the MDS matrix of Poseidon
+// the same way as snarkVM and arkworks
+type grainLFSR struct {
+	state [80]bool
+	head  int
+}
+
+func newGrainLFSR(fieldBits int, stateLen int, fullRounds int, partialRounds int) *grainLFSR {
+	lfsr := new(grainLFSR)
+
+	// b0, b1 = 0, 1 for a prime field, b2..b5 = 0 for the x^alpha S-box
+	lfsr.state[1] = true
+
+	// big-endian fields of the parameters
+	writeBits := func(from, to int, value int) {
+		for i := to; i >= from; i-- {
+			lfsr.state[i] = value&1 == 1
+			value >>= 1
+		}
+	}
+	writeBits(6, 17, fieldBits)
+	writeBits(18, 29, stateLen)
+	writeBits(30, 39, fullRounds)
+	writeBits(40, 49, partialRounds)
+
+	for i := 50; i < 80; i++ {
+		lfsr.state[i] = true
+	}
+
+	for i := 0; i < 160; i++ {
+		lfsr.update()
+	}
+
+	return lfsr
+}
+
+func (l *grainLFSR) update() bool {
+	bit := l.state[(l.head+62)%80] != l.state[(l.head+51)%80]
+	bit = bit != l.state[(l.head+38)%80]
+	bit = bit != l.state[(l.head+23)%80]
+	bit = bit != l.state[(l.head+13)%80]
+	bit = bit != l.state[l.head]
+
+	l.state[l.head] = bit
+	l.head = (l.head + 1) % 80
+
+	return bit
+}
+
+// bits returns the bits filtered by the self-shrinking generator: a bit is output only if the previous bit is set
+func (l *grainLFSR) bits(n int) []bool {
+	bits := make([]bool, n)
+	for i := range bits {
+		for !l.update() {
+			l.update()
+		}
+		bits[i] = l.update()
+	}
+
+	return bits
+}
+
+// bytesLE converts most-significant-bit-first bits to little-endian bytes
+func bytesLE(bits []bool) []byte {
+	buf := make([]byte, (len(bits)+7)/8)
+	for i, bit := range bits {
+		if bit {
+			j := len(bits) - 1 - i
+			buf[j/8] |= 1 << (j % 8)
+		}
+	}
+
+	return buf
+}
+
+// fieldElementsRejectionSampling generates field elements, rejecting the values, which are not less than the modulus
+func (l *grainLFSR) fieldElementsRejectionSampling(n int) []field.Base {
+	elements := make([]field.Base, n)
+	for i := range elements {
+		for {
+			buf := make([]byte, field.Size)
+			copy(buf, bytesLE(l.bits(modulusBits)))
+
+			if _, err := elements[i].SetBytesLE(buf); err == nil {
+				break
+			}
+		}
+	}
+
+	return elements
+}
+
+// fieldElementsModP generates field elements, reducing the values modulo the modulus
+func (l *grainLFSR) fieldElementsModP(n int) []field.Base {
+	elements := make([]field.Base, n)
+	for i := range elements {
+		elements[i].SetWideBytesLE(bytesLE(l.bits(modulusBits)))
+	}
+
+	return elements
+}
@@ -0,0 +1,383 @@
+package poseidon
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zkportal/aleo-utils-go/field"
+	"github.com/zkportal/aleo-utils-go/literal"
+)
+
+var ErrInvalidPlaintext = errors.New("invalid plaintext")
+
+// snarkVM literal variants
+const (
+	variantAddress = iota
+	variantBoolean
+	variantField
+	variantGroup
+	variantI8
+	variantI16
+	variantI32
+	variantI64
+	variantI128
+	variantU8
+	variantU16
+	variantU32
+	variantU64
+	variantU128
+	variantScalar
+	variantSignature
+	variantString
+)
+
+const (
+	// bits of a field element, a group x-coordinate and an address
+	fieldBits = 253
+	// bits of a scalar
+	scalarBits = 251
+	// bits of a field element, which can hold any value
+	fieldDataBits = 252
+)
+
+// bitWriter collects little-endian bits the same way as snarkVM's ToBits
+type bitWriter []bool
+
+func (w *bitWriter) writeUint(value uint64, n int) {
+	for i := 0; i < n; i++ {
+		*w = append(*w, (value>>i)&1 == 1)
+	}
+}
+
+func (w *bitWriter) writeBytes(buf []byte, n int) {
+	for i := 0; i < n; i++ {
+		*w = append(*w, (buf[i/8]>>(i%8))&1 == 1)
+	}
+}
+
+// plaintextParser parses a Leo plaintext: a literal, a struct or an array
+type plaintextParser struct {
+	s   string
+	pos int
+}
+
+func (p *plaintextParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s at offset %d", ErrInvalidPlaintext, fmt.Sprintf(format, args...), p.pos)
+}
+
+func (p *plaintextParser) skipSpaces() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// consume skips spaces and the character, returns false if the next character is different
+func (p *plaintextParser) consume(c byte) bool {
+	p.skipSpaces()
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+// plaintext parses a plaintext and returns its bits
+func (p *plaintextParser) plaintext() ([]bool, error) {
+	p.skipSpaces()
+	if p.pos == len(p.s) {
+		return nil, p.errorf("unexpected end")
+	}
+
+	switch p.s[p.pos] {
+	case '{':
+		return p.structure()
+	case '[':
+		return p.array()
+	default:
+		return p.literal()
+	}
+}
+
+func (p *plaintextParser) identifier() (string, error) {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		isLetter := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+		isDigit := c >= '0' && c <= '9'
+		if !isLetter && (p.pos == start || !isDigit && c != '_') {
+			break
+		}
+		p.pos++
+	}
+
+	if p.pos == start {
+		return "", p.errorf("expected an identifier")
+	}
+
+	// identifiers are stored in a field element
+	if p.pos-start > fieldDataBits/8 {
+		return "", p.errorf("identifier is too long")
+	}
+
+	return p.s[start:p.pos], nil
+}
+
+// structure encodes a struct: the member count, then the name and the value bits of every member
+func (p *plaintextParser) structure() ([]bool, error) {
+	p.consume('{')
+
+	type member struct {
+		name string
+		bits []bool
+	}
+	var members []member
+	names := map[string]bool{}
+
+	for !p.consume('}') {
+		if len(members) > 0 && !p.consume(',') {
+			return nil, p.errorf("expected ',' or '}'")
+		}
+
+		name, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+		if names[name] {
+			return nil, p.errorf("duplicate member %s", name)
+		}
+		names[name] = true
+
+		if !p.consume(':') {
+			return nil, p.errorf("expected ':'")
+		}
+
+		bits, err := p.plaintext()
+		if err != nil {
+			return nil, err
+		}
+		if len(bits) > 0xffff {
+			return nil, p.errorf("member %s is too large", name)
+		}
+
+		members = append(members, member{name: name, bits: bits})
+	}
+
+	if len(members) == 0 || len(members) > 0xff {
+		return nil, p.errorf("unsupported struct size %d", len(members))
+	}
+
+	w := bitWriter{false, true}
+	w.writeUint(uint64(len(members)), 8)
+	for _, m := range members {
+		w.writeUint(uint64(8*len(m.name)), 8)
+		w.writeBytes([]byte(m.name), 8*len(m.name))
+		w.writeUint(uint64(len(m.bits)), 16)
+		w = append(w, m.bits...)
+	}
+
+	return w, nil
+}
+
+// array encodes an array: the element count, then the bits of every element
+func (p *plaintextParser) array() ([]bool, error) {
+	p.consume('[')
+
+	var elements [][]bool
+	for !p.consume(']') {
+		if len(elements) > 0 && !p.consume(',') {
+			return nil, p.errorf("expected ',' or ']'")
+		}
+
+		bits, err := p.plaintext()
+		if err != nil {
+			return nil, err
+		}
+		if len(bits) > 0xffff {
+			return nil, p.errorf("array element is too large")
+		}
+
+		elements = append(elements, bits)
+	}
+
+	if len(elements) == 0 {
+		return nil, p.errorf("empty array")
+	}
+
+	w := bitWriter{true, false}
+	w.writeUint(uint64(len(elements)), 32)
+	for _, bits := range elements {
+		w.writeUint(uint64(len(bits)), 16)
+		w = append(w, bits...)
+	}
+
+	return w, nil
+}
+
+// literal encodes a literal: the variant, the size in bits and the value bits
+func (p *plaintextParser) literal() ([]bool, error) {
+	var token string
+	if p.s[p.pos] == '"' {
+		end := strings.IndexByte(p.s[p.pos+1:], '"')
+		if end < 0 {
+			return nil, p.errorf("unterminated string")
+		}
+		token = p.s[p.pos : p.pos+end+2]
+	} else {
+		end := strings.IndexAny(p.s[p.pos:], " \t\r\n,}]")
+		if end < 0 {
+			end = len(p.s) - p.pos
+		}
+		token = p.s[p.pos : p.pos+end]
+	}
+
+	variant, size, value, err := encodeLiteral(token)
+	if err != nil {
+		return nil, p.errorf("%v", err)
+	}
+	p.pos += len(token)
+
+	w := bitWriter{false, false}
+	w.writeUint(uint64(variant), 8)
+	w.writeUint(uint64(size), 16)
+	w.writeBytes(value, size)
+
+	return w, nil
+}
+
+// encodeLiteral returns the variant, the size in bits and the little-endian value of a literal
+func encodeLiteral(token string) (variant int, size int, value []byte, err error) {
+	switch {
+	case token == "true" || token == "false":
+		if token == "true" {
+			return variantBoolean, 1, []byte{1}, nil
+		}
+		return variantBoolean, 1, []byte{0}, nil
+
+	case strings.HasPrefix(token, `"`):
+		str := token[1 : len(token)-1]
+		if strings.IndexByte(str, '\\') >= 0 {
+			return 0, 0, nil, errors.New("escape sequences in strings are not supported")
+		}
+		if 8*len(str) > 0xffff {
+			return 0, 0, nil, errors.New("string is too long")
+		}
+		return variantString, 8 * len(str), []byte(str), nil
+
+	case strings.HasPrefix(token, "aleo1"):
+		address, err := literal.ParseAddress(token)
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		return variantAddress, fieldBits, address.BytesLE(), nil
+
+	case strings.HasPrefix(token, "sign1"):
+		signature, err := literal.ParseSignature(token)
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		// challenge and response scalars, and x-coordinates of the compute key
+		var w bitWriter
+		w.writeBytes(signature.Challenge.BytesLE(), scalarBits)
+		w.writeBytes(signature.Response.BytesLE(), scalarBits)
+		w.writeBytes(signature.PkSig.BytesLE(), fieldBits)
+		w.writeBytes(signature.PrSig.BytesLE(), fieldBits)
+		return variantSignature, len(w), packBits(w), nil
+
+	case strings.HasSuffix(token, "field"):
+		f, err := literal.ParseField(token)
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		return variantField, fieldBits, f.BytesLE(), nil
+
+	case strings.HasSuffix(token, "scalar"):
+		s, err := literal.ParseScalar(token)
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		return variantScalar, scalarBits, s.BytesLE(), nil
+
+	case strings.HasSuffix(token, "group"):
+		g, err := literal.ParseGroup(token)
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		return variantGroup, fieldBits, g.BytesLE(), nil
+	}
+
+	i, err := literal.ParseInteger(token)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	// signed integers come before unsigned ones
+	typ := i.Type()
+	if typ.Signed() {
+		variant = variantI8 + int(typ-literal.I8)
+	} else {
+		variant = variantU8 + int(typ-literal.U8)
+	}
+
+	return variant, typ.Bits(), i.BytesLE(), nil
+}
+
+// packBits packs little-endian bits to bytes
+func packBits(bits []bool) []byte {
+	buf := make([]byte, (len(bits)+7)/8)
+	for i, bit := range bits {
+		if bit {
+			buf[i/8] |= 1 << (i % 8)
+		}
+	}
+
+	return buf
+}
+
+// PlaintextFields encodes a Leo plaintext, e.g. `{ a: 1u8, b: [true, false] }`, to field elements the same way as
+// snarkVM's Plaintext::to_fields: the plaintext bits followed by a terminus bit, packed into 252-bit chunks.
+func PlaintextFields(plaintext string) ([]field.Base, error) {
+	p := &plaintextParser{s: plaintext}
+
+	bits, err := p.plaintext()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	if p.pos != len(p.s) {
+		return nil, p.errorf("unexpected trailing data")
+	}
+
+	bits = append(bits, true)
+
+	fields := make([]field.Base, 0, (len(bits)+fieldDataBits-1)/fieldDataBits)
+	for start := 0; start < len(bits); start += fieldDataBits {
+		buf := make([]byte, field.Size)
+		copy(buf, packBits(bits[start:min(start+fieldDataBits, len(bits))]))
+
+		var f field.Base
+		if _, err := f.SetBytesLE(buf); err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+
+	return fields, nil
+}
+
+// HashMessage hashes a Leo plaintext, e.g. a message formatted with Session.FormatMessage, with Poseidon8 and
+// returns the 16 little-endian bytes of the resulting u128. It's the same as Session.HashMessage, but doesn't need
+// the WASM module.
+func HashMessage(message []byte) ([]byte, error) {
+	fields, err := PlaintextFields(string(message))
+	if err != nil {
+		return nil, err
+	}
+
+	hash := Hash8(fields)
+
+	return ToU128(&hash), nil
+}
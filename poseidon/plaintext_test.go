@@ -0,0 +1,136 @@
+package poseidon
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// formattedMessage builds a single-chunk message in the Session.FormatMessage format
+func formattedMessage(first string) string {
+	var sb strings.Builder
+	sb.WriteString("{  c0: {")
+	for i := 0; i < 32; i++ {
+		value := "0u128"
+		if i == 0 {
+			value = first
+		}
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		fmt.Fprintf(&sb, "    f%d: %s", i, value)
+	}
+	sb.WriteString("  }}")
+
+	return sb.String()
+}
+
+func TestHashMessage(t *testing.T) {
+	// expected hashes are computed with Session.HashMessage
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"123u128", "075c30de71ced6278cb138c3b511facc"},
+		{"-128i8", "4ae78e725851cff5256d03cf318d741d"},
+		{"true", "f789fa7b45e6a89014ebcaa5cffa83b9"},
+		{"5field", "d85a1a9b3fec7565c5ebaca5b3715763"},
+		{"7scalar", "33883cf1c8a8f70e78177fe7793b40ff"},
+		{"0group", "7379bea14f8197f2331b9e960c64b9d9"},
+		{"aleo1lqmly7ez2k48ajf5hs92ulphaqr05qm4n8qwzj8v0yprmasgpqgsez59gg", "13f20bb992850cb3fc263f56008b5038"},
+		{`"hello"`, "a7a7ef51445e13450ebf94ba66c382bd"},
+		{"{ a: 1u8, b: { c: true } }", "eddbcfd8345e6364b5b91a8894bebdcc"},
+		{"[1u8, 2u8, 3u8]", "b4dd66196f66454d667433dec816a993"},
+		// "btc/usd = 1.0" formatted with Session.FormatMessage
+		{formattedMessage("3817247500744944152259967808610u128"), "b134060d76cd898995a605c532d4db0f"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.message[:min(len(tt.message), 32)], func(t *testing.T) {
+			got, err := HashMessage([]byte(tt.message))
+			if err != nil {
+				t.Fatalf("HashMessage() error = %v", err)
+			}
+			if hex.EncodeToString(got) != tt.want {
+				t.Errorf("HashMessage() = %x, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashMessage_Whitespace(t *testing.T) {
+	want, err := HashMessage([]byte("{ a: 1u8, b: [true, false] }"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := HashMessage([]byte("\n{a:1u8,\tb:[ true ,false ]}\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("HashMessage() = %x, want %x", got, want)
+	}
+}
+
+func TestHashMessage_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"   ",
+		"1",
+		"256u8",
+		"1u8 2u8",
+		"{}",
+		"[]",
+		"{ a: 1u8, a: 2u8 }",
+		"{ a 1u8 }",
+		"{ a: 1u8 b: 2u8 }",
+		"{ 1a: 1u8 }",
+		"[1u8 2u8]",
+		"{ a: 1u8",
+		`"unterminated`,
+		`"escaped\"quote"`,
+		"aleo1invalid",
+		"sign1invalid",
+		"1group",
+	}
+	for _, message := range tests {
+		if _, err := HashMessage([]byte(message)); err == nil {
+			t.Errorf("HashMessage(%q) should fail", message)
+		} else if !errors.Is(err, ErrInvalidPlaintext) {
+			t.Errorf("HashMessage(%q) error = %v, want ErrInvalidPlaintext", message, err)
+		}
+	}
+}
+
+func TestPlaintextFields(t *testing.T) {
+	// a u128 literal takes 2 + 8 + 16 + 128 bits and a terminus bit, which fit in one field element
+	fields, err := PlaintextFields("1u128")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fields) != 1 {
+		t.Errorf("PlaintextFields() returned %d elements, want 1", len(fields))
+	}
+
+	// a formatted message is larger than a field element
+	fields, err = PlaintextFields(formattedMessage("1u128"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fields) < 2 {
+		t.Errorf("PlaintextFields() returned %d elements, want more than 1", len(fields))
+	}
+}
+
+func BenchmarkHashMessage(b *testing.B) {
+	message := []byte(formattedMessage("3817247500744944152259967808610u128"))
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := HashMessage(message); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
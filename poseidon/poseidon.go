@@ -0,0 +1,175 @@
+// Package poseidon implements the Poseidon hash over the Aleo base field in pure Go, with the same parameters and
+// domain separation as snarkVM. Hash8 is byte-identical to snarkVM's hash_psd8, which Session.HashMessage uses.
+// Hash2 and Hash4 are identical to hash_psd2 and hash_psd4.
+package poseidon
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/zkportal/aleo-utils-go/field"
+)
+
+const (
+	// number of bits of the base field modulus
+	modulusBits = 253
+	// S-box exponent
+	alpha = 17
+	// the sponge capacity
+	capacity      = 1
+	fullRounds    = 8
+	partialRounds = 31
+	// number of bits of a hash which are kept when it's converted to a scalar, the scalar field capacity
+	scalarDataBits = 250
+)
+
+// Poseidon is a Poseidon hash instance with a rate, it's safe for concurrent use.
+type Poseidon struct {
+	rate   int
+	domain field.Base
+	// round constants, one row per round
+	ark [][]field.Base
+	mds [][]field.Base
+}
+
+// New creates a Poseidon instance with the rate, which is one of 2, 4 or 8, and the snarkVM domain separator
+// `AleoPoseidon<rate>`. Generating the parameters is relatively slow, so reuse the instances or use Hash2, Hash4
+// and Hash8.
+func New(rate int) (*Poseidon, error) {
+	if rate != 2 && rate != 4 && rate != 8 {
+		return nil, fmt.Errorf("unsupported Poseidon rate %d", rate)
+	}
+
+	width := rate + capacity
+	lfsr := newGrainLFSR(modulusBits, width, fullRounds, partialRounds)
+
+	ark := make([][]field.Base, fullRounds+partialRounds)
+	for i := range ark {
+		ark[i] = lfsr.fieldElementsRejectionSampling(width)
+	}
+
+	xs := lfsr.fieldElementsModP(width)
+	ys := lfsr.fieldElementsModP(width)
+
+	// Cauchy matrix
+	mds := make([][]field.Base, width)
+	for i := range mds {
+		mds[i] = make([]field.Base, width)
+		for j := range mds[i] {
+			var sum field.Base
+			mds[i][j].Inverse(sum.Add(&xs[i], &ys[j]))
+		}
+	}
+
+	p := &Poseidon{
+		rate: rate,
+		ark:  ark,
+		mds:  mds,
+	}
+	// the domain separator is the little-endian integer of the domain bytes
+	p.domain.SetWideBytesLE([]byte("AleoPoseidon" + strconv.Itoa(rate)))
+
+	return p, nil
+}
+
+// Rate returns the number of field elements absorbed per permutation.
+func (p *Poseidon) Rate() int {
+	return p.rate
+}
+
+// Hash hashes the field elements to a single field element.
+func (p *Poseidon) Hash(input []field.Base) field.Base {
+	return p.HashMany(input, 1)[0]
+}
+
+// HashMany hashes the field elements to the number of field elements.
+func (p *Poseidon) HashMany(input []field.Base, outputs int) []field.Base {
+	// the preimage is the domain, the input length, zero padding up to the rate, and the input
+	preimage := make([]field.Base, p.rate, p.rate+len(input))
+	preimage[0] = p.domain
+	preimage[1].SetUint64(uint64(len(input)))
+	preimage = append(preimage, input...)
+
+	s := newSponge(p)
+	s.absorb(preimage)
+
+	return s.squeeze(outputs)
+}
+
+// HashToScalar hashes the field elements to a scalar, the same as snarkVM's hash_to_scalar: the hash is truncated
+// to the scalar field capacity.
+func (p *Poseidon) HashToScalar(input []field.Base) field.Scalar {
+	hash := p.Hash(input)
+	buf := hash.BytesLE()
+
+	// clear the bits above the capacity
+	buf[scalarDataBits/8] &= 1<<(scalarDataBits%8) - 1
+	for i := scalarDataBits/8 + 1; i < len(buf); i++ {
+		buf[i] = 0
+	}
+
+	var s field.Scalar
+	if _, err := s.SetBytesLE(buf); err != nil {
+		// unreachable, the truncated hash is less than the scalar modulus
+		panic(err)
+	}
+
+	return s
+}
+
+var (
+	instances     = map[int]*Poseidon{}
+	instancesOnce = map[int]*sync.Once{2: {}, 4: {}, 8: {}}
+)
+
+func instance(rate int) *Poseidon {
+	instancesOnce[rate].Do(func() {
+		p, err := New(rate)
+		if err != nil {
+			panic(err)
+		}
+		instances[rate] = p
+	})
+
+	return instances[rate]
+}
+
+// Hash2 hashes the field elements with Poseidon with the rate 2, the same as snarkVM's hash_psd2.
+func Hash2(input []field.Base) field.Base {
+	return instance(2).Hash(input)
+}
+
+// Hash4 hashes the field elements with Poseidon with the rate 4, the same as snarkVM's hash_psd4.
+func Hash4(input []field.Base) field.Base {
+	return instance(4).Hash(input)
+}
+
+// Hash8 hashes the field elements with Poseidon with the rate 8, the same as snarkVM's hash_psd8.
+func Hash8(input []field.Base) field.Base {
+	return instance(8).Hash(input)
+}
+
+// HashToScalar2 hashes the field elements to a scalar with Poseidon with the rate 2, the same as snarkVM's
+// hash_to_scalar_psd2.
+func HashToScalar2(input []field.Base) field.Scalar {
+	return instance(2).HashToScalar(input)
+}
+
+// HashToScalar4 hashes the field elements to a scalar with Poseidon with the rate 4, the same as snarkVM's
+// hash_to_scalar_psd4.
+func HashToScalar4(input []field.Base) field.Scalar {
+	return instance(4).HashToScalar(input)
+}
+
+// HashToScalar8 hashes the field elements to a scalar with Poseidon with the rate 8, the same as snarkVM's
+// hash_to_scalar_psd8.
+func HashToScalar8(input []field.Base) field.Scalar {
+	return instance(8).HashToScalar(input)
+}
+
+// ToU128 casts a hash to u128 the same way Leo and Session.HashMessage do, by truncating it to the lower 128 bits.
+// Returns 16 little-endian bytes, the same as the result of Session.HashMessage.
+func ToU128(hash *field.Base) []byte {
+	return hash.BytesLE()[:16]
+}
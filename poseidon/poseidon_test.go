@@ -0,0 +1,166 @@
+package poseidon
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/zkportal/aleo-utils-go/field"
+)
+
+func fields(values ...uint64) []field.Base {
+	elements := make([]field.Base, len(values))
+	for i, v := range values {
+		elements[i].SetUint64(v)
+	}
+
+	return elements
+}
+
+func TestHash(t *testing.T) {
+	digits := fields(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+
+	tests := []struct {
+		name  string
+		hash  func([]field.Base) field.Base
+		input []field.Base
+		want  string
+	}{
+		{"psd2 empty", Hash2, nil, "5959576240184833951274960640648153555176218428621163945077269208950119747771field"},
+		{"psd2 one", Hash2, fields(1), "107338370619223092068132723896052301422983572279086036748479670139944641824field"},
+		{"psd2 digits", Hash2, digits, "1034767085804474475110801604803092951956784149630022117982464051112462600687field"},
+		{"psd4 empty", Hash4, nil, "7196703107433800454858787951765682358737857309038035338614525537102918225225field"},
+		{"psd4 one", Hash4, fields(1), "4118718987748259783013585284985897557152498697752820235066334744459981242055field"},
+		{"psd4 digits", Hash4, digits, "5874592466980945977251124377334943601704688820521891011986642653367495560383field"},
+		{"psd8 empty", Hash8, nil, "8194066709171998904160308966279627998268379913279917980650357564952431231799field"},
+		{"psd8 one", Hash8, fields(1), "5979392658650639904568495682277601692484903308279058440836580197467543126506field"},
+		{"psd8 digits", Hash8, digits, "2210290420742684413383916282638852496101317092157188068201197391245308266304field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.hash(tt.input)
+			if got.String() != tt.want {
+				t.Errorf("hash = %s, want %s", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestHashToScalar(t *testing.T) {
+	digits := fields(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+
+	// the hashes are truncated to 250 bits
+	tests := []struct {
+		name  string
+		hash  func([]field.Base) field.Scalar
+		input []field.Base
+		want  string
+	}{
+		{"psd2 empty", HashToScalar2, nil, "531822057185637290795070718365907874554187897419262505727694958579191795899scalar"},
+		{"psd4 digits", HashToScalar4, digits, "446838283981749316771234455052697921082658289319989572637068402996567608511scalar"},
+		{"psd8 digits", HashToScalar8, digits, "401039026409618859890619641878103935893973581756554255084672641121665615680scalar"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.hash(tt.input)
+			if got.String() != tt.want {
+				t.Errorf("hash = %s, want %s", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestHashMany(t *testing.T) {
+	p, err := New(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// three outputs need two permutations with the rate 2
+	want := []string{
+		"331654914538502470743870876231218532880590298544129720907024905288306182975field",
+		"3328943808580943386826700781680796497003201925421993369538756285866629887386field",
+		"2266952449813637753944362452532996891931950601698962633728183682419734539878field",
+	}
+
+	got := p.HashMany(fields(1, 2), len(want))
+	if len(got) != len(want) {
+		t.Fatalf("HashMany() returned %d elements, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].String() != want[i] {
+			t.Errorf("HashMany()[%d] = %s, want %s", i, got[i].String(), want[i])
+		}
+	}
+
+	if hash := p.Hash(fields(1, 2)); hash.Equal(&got[0]) != 1 {
+		t.Errorf("Hash() = %s, want the first element of HashMany() %s", hash.String(), got[0].String())
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, rate := range []int{0, 1, 3, 16} {
+		if _, err := New(rate); err == nil {
+			t.Errorf("New(%d) should fail", rate)
+		}
+	}
+
+	p, err := New(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Rate() != 4 {
+		t.Errorf("Rate() = %d, want 4", p.Rate())
+	}
+
+	// a new instance is the same as the shared one
+	got, want := p.Hash(fields(1)), Hash4(fields(1))
+	if got.Equal(&want) != 1 {
+		t.Errorf("Hash() = %s, Hash4() = %s", got.String(), want.String())
+	}
+}
+
+func TestToU128(t *testing.T) {
+	var hash field.Base
+	hash.SetString("340282366920938463463374607431768211457field") // 2^128 + 1
+
+	got := ToU128(&hash)
+	want := append([]byte{1}, make([]byte, 15)...)
+	if string(got) != string(want) {
+		t.Errorf("ToU128() = %x, want %x", got, want)
+	}
+}
+
+func TestConcurrentHash(t *testing.T) {
+	want := Hash8(fields(1, 2, 3))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := Hash8(fields(1, 2, 3)); got.Equal(&want) != 1 {
+				t.Errorf("Hash8() = %s, want %s", got.String(), want.String())
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkHash8(b *testing.B) {
+	input := fields(1)
+	Hash8(input)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Hash8(input)
+	}
+}
+
+func BenchmarkNew(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := New(8); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
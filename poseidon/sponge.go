@@ -0,0 +1,110 @@
+package poseidon
+
+import (
+	"github.com/zkportal/aleo-utils-go/field"
+)
+
+// sponge is the duplex sponge of snarkVM's Poseidon
+type sponge struct {
+	params *Poseidon
+	// the capacity element followed by the rate elements
+	state []field.Base
+	// true if the sponge is squeezing
+	squeezing bool
+	// next rate element to absorb into or squeeze from
+	next int
+}
+
+func newSponge(params *Poseidon) *sponge {
+	return &sponge{
+		params: params,
+		state:  make([]field.Base, capacity+params.rate),
+	}
+}
+
+func (s *sponge) absorb(input []field.Base) {
+	if s.squeezing || s.next == s.params.rate {
+		s.permute()
+		s.next = 0
+	}
+	s.squeezing = false
+
+	for len(input) > 0 {
+		if s.next == s.params.rate {
+			s.permute()
+			s.next = 0
+		}
+
+		n := min(s.params.rate-s.next, len(input))
+		for i := 0; i < n; i++ {
+			e := &s.state[capacity+s.next+i]
+			e.Add(e, &input[i])
+		}
+
+		s.next += n
+		input = input[n:]
+	}
+}
+
+func (s *sponge) squeeze(n int) []field.Base {
+	if !s.squeezing || s.next == s.params.rate {
+		s.permute()
+		s.next = 0
+	}
+	s.squeezing = true
+
+	output := make([]field.Base, 0, n)
+	for len(output) < n {
+		if s.next == s.params.rate {
+			s.permute()
+			s.next = 0
+		}
+
+		count := min(s.params.rate-s.next, n-len(output))
+		output = append(output, s.state[capacity+s.next:capacity+s.next+count]...)
+		s.next += count
+	}
+
+	return output
+}
+
+// sBox sets e = e^17
+func sBox(e *field.Base) {
+	var x16 field.Base
+	x16.Square(e)
+	x16.Square(&x16)
+	x16.Square(&x16)
+	x16.Square(&x16)
+	e.Mul(e, &x16)
+}
+
+func (s *sponge) permute() {
+	p := s.params
+	width := len(s.state)
+	next := make([]field.Base, width)
+
+	for round := 0; round < fullRounds+partialRounds; round++ {
+		full := round < fullRounds/2 || round >= fullRounds/2+partialRounds
+
+		for i := range s.state {
+			s.state[i].Add(&s.state[i], &p.ark[round][i])
+		}
+
+		if full {
+			for i := range s.state {
+				sBox(&s.state[i])
+			}
+		} else {
+			sBox(&s.state[0])
+		}
+
+		for i := range next {
+			next[i].Zero()
+			for j := range s.state {
+				var product field.Base
+				next[i].Add(&next[i], product.Mul(&s.state[j], &p.mds[i][j]))
+			}
+		}
+		copy(s.state, next)
+	}
+}
@@ -0,0 +1,127 @@
+package aleo_utils
+
+import (
+	"errors"
+	"log"
+)
+
+// ProgramAddress returns the address of a program, e.g. `credits.aleo`. Programs hold public balances
+// under their addresses.
+func (s *aleoWrapperSession) ProgramAddress(programID string) (address string, err error) {
+	if s.mod == nil || s.mod.IsClosed() {
+		return "", ErrNoModule
+	}
+
+	if s.programAddress == nil {
+		return "", ErrNotSupported
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// find out exactly what the error was and set err
+			switch x := r.(type) {
+			case string:
+				err = errors.New(x)
+			case error:
+				err = x
+			default:
+				err = errors.New("unknown panic")
+			}
+			address = ""
+		}
+	}()
+
+	address, err = s.callWithStrings(s.programAddress, []string{programID})
+	if err != nil {
+		log.Println("program_address error:", err)
+		return "", errors.New("failed to compute program address")
+	}
+
+	return address, nil
+}
+
+// MappingKeyID computes the identifier of a mapping entry, which is used to look up mapping values in the node
+// storage. The key is a Leo plaintext, e.g. an address literal for `credits.aleo/account`. Returns the key ID
+// as a field literal.
+func (s *aleoWrapperSession) MappingKeyID(programID string, mappingName string, key string) (keyID string, err error) {
+	if s.mod == nil || s.mod.IsClosed() {
+		return "", ErrNoModule
+	}
+
+	if s.mappingKeyID == nil {
+		return "", ErrNotSupported
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// find out exactly what the error was and set err
+			switch x := r.(type) {
+			case string:
+				err = errors.New(x)
+			case error:
+				err = x
+			default:
+				err = errors.New("unknown panic")
+			}
+			keyID = ""
+		}
+	}()
+
+	keyID, err = s.callWithStrings(s.mappingKeyID, []string{programID, mappingName, key})
+	if err != nil {
+		log.Println("mapping_key_id error:", err)
+		return "", errors.New("failed to compute mapping key ID")
+	}
+
+	return keyID, nil
+}
+
+// PlaintextToFields encodes a Leo plaintext to field elements the same way snarkVM does before hashing or signing it,
+// e.g. for hashing the value with Poseidon in a Leo program. Returns the fields as field literals.
+func (s *aleoWrapperSession) PlaintextToFields(plaintext string) (fields []string, err error) {
+	if s.mod == nil || s.mod.IsClosed() {
+		return nil, ErrNoModule
+	}
+
+	if s.plaintextToFields == nil {
+		return nil, ErrNotSupported
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// find out exactly what the error was and set err
+			switch x := r.(type) {
+			case string:
+				err = errors.New(x)
+			case error:
+				err = x
+			default:
+				err = errors.New("unknown panic")
+			}
+			fields = nil
+		}
+	}()
+
+	result, err := s.callStrings(s.plaintextToFields, []string{plaintext})
+	if err != nil {
+		log.Println("plaintext_to_fields error:", err)
+		return nil, errors.New("failed to convert plaintext to fields")
+	}
+
+	buf, ok := s.readResult(unpackResult(result))
+	if !ok {
+		return nil, errors.New("failed to convert plaintext to fields")
+	}
+
+	packed, ok := unpackAllFields(buf, 1)
+	if !ok {
+		return nil, errors.New("invalid fields returned by the module")
+	}
+
+	fields = make([]string, len(packed))
+	for i, field := range packed {
+		fields[i] = string(field)
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,309 @@
+package aleo_utils
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/zkportal/aleo-utils-go/codec"
+	"github.com/zkportal/aleo-utils-go/literal"
+)
+
+// results of the module is_owner function
+const (
+	recordNotOwner = 0
+	recordOwner    = 1
+)
+
+// DecryptRecord decrypts a `record1...` record ciphertext with a view key of the record owner, returns the plaintext
+// record in the Leo format, e.g. `{ owner: aleo1....private, microcredits: 100u64.private, _nonce: ...group.public }`.
+func (s *aleoWrapperSession) DecryptRecord(viewKey string, ciphertext string) (record string, err error) {
+	if s.mod == nil || s.mod.IsClosed() {
+		return "", ErrNoModule
+	}
+
+	if s.decryptRecord == nil {
+		return "", ErrNotSupported
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// find out exactly what the error was and set err
+			switch x := r.(type) {
+			case string:
+				err = errors.New(x)
+			case error:
+				err = x
+			default:
+				err = errors.New("unknown panic")
+			}
+			record = ""
+		}
+	}()
+
+	viewKeyPtr, ciphertextPtr, err := s.putRecordArguments(viewKey, ciphertext)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := s.call(s.decryptRecord, viewKeyPtr, VIEW_KEY_SIZE, ciphertextPtr, uint64(len(ciphertext)))
+	if err != nil {
+		log.Println("decrypt_record error:", err)
+		return "", errors.New("failed to decrypt record")
+	}
+	if result == 0 {
+		return "", errors.New("internal error when decrypting record")
+	}
+
+	record, ok := s.readResultString(unpackResult(result))
+	if !ok {
+		return "", errors.New("failed to decrypt record")
+	}
+
+	return record, nil
+}
+
+// IsOwner checks if the view key owns the record ciphertext. It's cheaper than DecryptRecord, so use it to scan
+// records before decrypting them.
+func (s *aleoWrapperSession) IsOwner(viewKey string, ciphertext string) (owner bool, err error) {
+	if s.mod == nil || s.mod.IsClosed() {
+		return false, ErrNoModule
+	}
+
+	if s.isOwner == nil {
+		return false, ErrNotSupported
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// find out exactly what the error was and set err
+			switch x := r.(type) {
+			case string:
+				err = errors.New(x)
+			case error:
+				err = x
+			default:
+				err = errors.New("unknown panic")
+			}
+			owner = false
+		}
+	}()
+
+	viewKeyPtr, ciphertextPtr, err := s.putRecordArguments(viewKey, ciphertext)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := s.call(s.isOwner, viewKeyPtr, VIEW_KEY_SIZE, ciphertextPtr, uint64(len(ciphertext)))
+	if err != nil {
+		log.Println("is_owner error:", err)
+		return false, errors.New("failed to check record owner")
+	}
+
+	switch uint32(result) {
+	case recordOwner:
+		return true, nil
+	case recordNotOwner:
+		return false, nil
+	default:
+		return false, errors.New("internal error when checking record owner")
+	}
+}
+
+// putRecordArguments validates a view key and a record ciphertext, and writes them to the arena.
+func (s *aleoWrapperSession) putRecordArguments(viewKey string, ciphertext string) (viewKeyPtr uint64, ciphertextPtr uint64, err error) {
+	if len(viewKey) != VIEW_KEY_SIZE {
+		return 0, 0, errors.New("invalid view key size")
+	}
+	if err := codec.ValidateViewKey(viewKey); err != nil {
+		return 0, 0, err
+	}
+
+	if err := codec.ValidateRecordCiphertext(ciphertext); err != nil {
+		return 0, 0, err
+	}
+
+	if err := s.resetArena(VIEW_KEY_SIZE + len(ciphertext)); err != nil {
+		log.Println("arena error:", err)
+		return 0, 0, errors.New("failed to allocate memory for record")
+	}
+
+	// write view key to wasm memory
+	viewKeyPtr, err = s.putString(viewKey)
+	if err != nil {
+		return 0, 0, errors.New("failed to write view key to memory")
+	}
+
+	// write record ciphertext to wasm memory
+	ciphertextPtr, err = s.putString(ciphertext)
+	if err != nil {
+		return 0, 0, errors.New("failed to write record to memory")
+	}
+
+	return viewKeyPtr, ciphertextPtr, nil
+}
+
+// EncryptRecord encrypts a plaintext record in the Leo format to its owner. The randomizer is a scalar literal,
+// e.g. `123scalar`, the record nonce is replaced with the nonce derived from the randomizer. Returns the `record1...`
+// record ciphertext.
+func (s *aleoWrapperSession) EncryptRecord(record string, randomizer string) (ciphertext string, err error) {
+	if s.mod == nil || s.mod.IsClosed() {
+		return "", ErrNoModule
+	}
+
+	if s.encryptRecord == nil {
+		return "", ErrNotSupported
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// find out exactly what the error was and set err
+			switch x := r.(type) {
+			case string:
+				err = errors.New(x)
+			case error:
+				err = x
+			default:
+				err = errors.New("unknown panic")
+			}
+			ciphertext = ""
+		}
+	}()
+
+	if _, err := literal.ParseScalar(randomizer); err != nil {
+		return "", err
+	}
+
+	ciphertext, err = s.callWithStrings(s.encryptRecord, []string{record, randomizer})
+	if err != nil {
+		log.Println("encrypt_record error:", err)
+		return "", errors.New("failed to encrypt record")
+	}
+
+	return ciphertext, nil
+}
+
+// RecordCommitment computes the commitment of a plaintext record, e.g. `credits` record of `credits.aleo` program.
+// Returns the commitment as a field literal.
+func (s *aleoWrapperSession) RecordCommitment(record string, programID string, recordName string) (commitment string, err error) {
+	if s.mod == nil || s.mod.IsClosed() {
+		return "", ErrNoModule
+	}
+
+	if s.recordCommitment == nil {
+		return "", ErrNotSupported
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// find out exactly what the error was and set err
+			switch x := r.(type) {
+			case string:
+				err = errors.New(x)
+			case error:
+				err = x
+			default:
+				err = errors.New("unknown panic")
+			}
+			commitment = ""
+		}
+	}()
+
+	commitment, err = s.callWithStrings(s.recordCommitment, []string{record, programID, recordName})
+	if err != nil {
+		log.Println("record_commitment error:", err)
+		return "", errors.New("failed to compute record commitment")
+	}
+
+	return commitment, nil
+}
+
+// RecordSerialNumber computes the serial number of a record from the owner's private key and the record commitment,
+// which is a field literal. A record is spent if its serial number is published on chain.
+func (s *aleoWrapperSession) RecordSerialNumber(key string, commitment string) (serialNumber string, err error) {
+	if s.mod == nil || s.mod.IsClosed() {
+		return "", ErrNoModule
+	}
+
+	if s.recordSerialNumber == nil {
+		return "", ErrNotSupported
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// find out exactly what the error was and set err
+			switch x := r.(type) {
+			case string:
+				err = errors.New(x)
+			case error:
+				err = x
+			default:
+				err = errors.New("unknown panic")
+			}
+			serialNumber = ""
+		}
+	}()
+
+	if err := codec.ValidatePrivateKey(key); err != nil {
+		return "", err
+	}
+	if _, err := literal.ParseField(commitment); err != nil {
+		return "", err
+	}
+
+	serialNumber, err = s.callWithStrings(s.recordSerialNumber, []string{key, commitment})
+	if err != nil {
+		log.Println("record_serial_number error:", err)
+		return "", errors.New("failed to compute record serial number")
+	}
+
+	return serialNumber, nil
+}
+
+// RecordTag computes the tag of a record from the record commitment, which is a field literal, and the owner's
+// private key, view key or graph key. Unlike the serial number, the tag can be computed without the private key.
+func (s *aleoWrapperSession) RecordTag(key string, commitment string) (tag string, err error) {
+	if s.mod == nil || s.mod.IsClosed() {
+		return "", ErrNoModule
+	}
+
+	if s.recordTag == nil {
+		return "", ErrNotSupported
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// find out exactly what the error was and set err
+			switch x := r.(type) {
+			case string:
+				err = errors.New(x)
+			case error:
+				err = x
+			default:
+				err = errors.New("unknown panic")
+			}
+			tag = ""
+		}
+	}()
+
+	switch {
+	case strings.HasPrefix(key, codec.PrivateKeyPrefix):
+		err = codec.ValidatePrivateKey(key)
+	case strings.HasPrefix(key, codec.ViewKeyPrefix):
+		err = codec.ValidateViewKey(key)
+	}
+	if err != nil {
+		return "", err
+	}
+	if _, err := literal.ParseField(commitment); err != nil {
+		return "", err
+	}
+
+	tag, err = s.callWithStrings(s.recordTag, []string{key, commitment})
+	if err != nil {
+		log.Println("record_tag error:", err)
+		return "", errors.New("failed to compute record tag")
+	}
+
+	return tag, nil
+}
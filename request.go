@@ -0,0 +1,58 @@
+package aleo_utils
+
+import (
+	"errors"
+	"log"
+
+	"github.com/zkportal/aleo-utils-go/codec"
+)
+
+// SignRequest signs a request to execute a program function, e.g. `transfer_public` of `credits.aleo`. The inputs are
+// Leo values, e.g. `aleo1...` or `100u64`, and the input types are the function input types, e.g. `address.public`,
+// `u64.public` or `credits.record`. Returns the signed request serialized as JSON, which includes the transition view key,
+// the transition commitment and the input IDs, and can be authorized and executed by a prover.
+func (s *aleoWrapperSession) SignRequest(privateKey string, programID string, functionName string, inputs []string, inputTypes []string) (request string, err error) {
+	if s.mod == nil || s.mod.IsClosed() {
+		return "", ErrNoModule
+	}
+
+	if s.signRequest == nil {
+		return "", ErrNotSupported
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// find out exactly what the error was and set err
+			switch x := r.(type) {
+			case string:
+				err = errors.New(x)
+			case error:
+				err = x
+			default:
+				err = errors.New("unknown panic")
+			}
+			request = ""
+		}
+	}()
+
+	if len(inputs) != len(inputTypes) {
+		return "", errors.New("number of inputs doesn't match number of input types")
+	}
+
+	if len(privateKey) != PRIVATE_KEY_SIZE {
+		return "", errors.New("invalid private key size")
+	}
+	if err := codec.ValidatePrivateKey(privateKey); err != nil {
+		return "", err
+	}
+
+	args := []string{privateKey, programID, functionName, packStrings(inputs), packStrings(inputTypes)}
+
+	request, err = s.callWithStrings(s.signRequest, args)
+	if err != nil {
+		log.Println("sign_request error:", err)
+		return "", errors.New("failed to sign request")
+	}
+
+	return request, nil
+}
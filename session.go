@@ -3,15 +3,21 @@ package aleo_utils
 import (
 	"context"
 	"errors"
-	"fmt"
 	"log"
-	"strings"
 
 	"github.com/tetratelabs/wazero/api"
+	"github.com/zkportal/aleo-utils-go/codec"
+)
+
+// flags of the module signing functions
+const (
+	signSkipSelfCheck      = 1
+	signDeterministicNonce = 2
 )
 
 var (
-	ErrNoModule = errors.New("session module is closed")
+	ErrNoModule     = errors.New("session module is closed")
+	ErrNotSupported = errors.New("function is not supported by the wrapper module")
 )
 
 // Provides access to wrapper functionality. A session is not goroutine safe so
@@ -23,6 +29,31 @@ type Session interface {
 	HashMessageToString(message []byte) (hash string, err error)
 	HashMessage(message []byte) (hash []byte, err error)
 	Sign(key string, message []byte) (signature string, err error)
+	SignWithOptions(key string, message []byte, opts SignOptions) (signature string, err error)
+	Verify(address string, message []byte, signature string) (valid bool, err error)
+	DecryptRecord(viewKey string, ciphertext string) (record string, err error)
+	IsOwner(viewKey string, ciphertext string) (owner bool, err error)
+	EncryptRecord(record string, randomizer string) (ciphertext string, err error)
+	RecordCommitment(record string, programID string, recordName string) (commitment string, err error)
+	RecordSerialNumber(key string, commitment string) (serialNumber string, err error)
+	RecordTag(key string, commitment string) (tag string, err error)
+	TransitionViewKey(viewKey string, tpk string) (tvk string, err error)
+	DecryptTransitionInput(tvk string, programID string, functionName string, index int, ciphertext string) (plaintext string, err error)
+	DecryptTransitionOutput(tvk string, programID string, functionName string, numInputs int, index int, ciphertext string) (plaintext string, err error)
+	ProgramAddress(programID string) (address string, err error)
+	MappingKeyID(programID string, mappingName string, key string) (keyID string, err error)
+	PlaintextToFields(plaintext string) (fields []string, err error)
+	SignRequest(privateKey string, programID string, functionName string, inputs []string, inputTypes []string) (request string, err error)
+	SignFeePublic(privateKey string, executionID string, baseFee uint64, priorityFee uint64) (authorization *Authorization, err error)
+	ParseTransaction(transaction []byte) (tx *Transaction, err error)
+	CheckTransactionConsistency(transaction []byte) (failures []TransactionCheckFailure, err error)
+	FormatAndHash(message []byte, targetChunks int) (result *FormattedHash, err error)
+	FormatHashSign(key string, message []byte, targetChunks int) (result *SignedMessage, err error)
+	HashMessageBatch(messages [][]byte) (results []HashResult, err error)
+	SignBatch(key string, messages [][]byte) (results []SignatureResult, err error)
+	LoadKey(key string) (handle *KeyHandle, err error)
+	SignWithHandle(handle *KeyHandle, message []byte) (signature string, err error)
+	SignWithHandleOptions(handle *KeyHandle, message []byte, opts SignOptions) (signature string, err error)
 
 	Close()
 }
@@ -35,19 +66,65 @@ type aleoWrapperSession struct {
 	mod api.Module
 	ctx context.Context
 
-	newPrivateKey    api.Function
-	getAddress       api.Function
-	sign             api.Function
-	allocate         api.Function
-	deallocate       api.Function
-	hashMessage      api.Function
-	hashMessageBytes api.Function
-	formatMessage    api.Function
-	recoverMessage   api.Function
+	newPrivateKey      api.Function
+	getAddress         api.Function
+	sign               api.Function
+	allocate           api.Function
+	deallocate         api.Function
+	hashMessage        api.Function
+	hashMessageBytes   api.Function
+	formatMessage      api.Function
+	recoverMessage     api.Function
+	verify             api.Function
+	formatAndHash      api.Function
+	formatHashSign     api.Function
+	hashMessageBatch   api.Function
+	signBatch          api.Function
+	loadKey            api.Function
+	releaseKey         api.Function
+	releaseAllKeys     api.Function
+	signWithKey        api.Function
+	signWithOptions    api.Function
+	decryptRecord      api.Function
+	isOwner            api.Function
+	encryptRecord      api.Function
+	recordCommitment   api.Function
+	recordSerialNumber api.Function
+	recordTag          api.Function
+	transitionViewKey  api.Function
+	decryptTransition  api.Function
+	programAddress     api.Function
+	mappingKeyID       api.Function
+	plaintextToFields  api.Function
+	signRequest        api.Function
+	authorizeFee       api.Function
+	parseTransaction   api.Function
+	checkTransaction   api.Function
+
+	// default options for Sign
+	signOptions SignOptions
+	// keys loaded with LoadKey by their module handle IDs
+	handles map[uint32]*KeyHandle
+	// whether the module supports SignOptions.DeterministicNonce
+	deterministicNonce bool
+
+	// scratch buffer in the guest memory for passing arguments
+	arena guestArena
+	// reusable stack for guest function parameters and results
+	stack [maxCallParams]uint64
 }
 
+// Close wipes the keys loaded with LoadKey and closes the session module.
 func (session *aleoWrapperSession) Close() {
+	for id, handle := range session.handles {
+		handle.id = 0
+		delete(session.handles, id)
+	}
+
 	if session.mod != nil {
+		if session.releaseAllKeys != nil && !session.mod.IsClosed() {
+			session.call(session.releaseAllKeys)
+		}
 		session.mod.Close(context.Background())
 	}
 }
@@ -75,49 +152,41 @@ func (s *aleoWrapperSession) NewPrivateKey() (key string, address string, err er
 	}()
 
 	// generate new private key
-	var privKeyPtr []uint64
-	privKeyPtr, err = s.newPrivateKey.Call(s.ctx)
+	privKeyPtr, err := s.call(s.newPrivateKey)
 	if err != nil {
 		log.Println("new_private_key error:", err)
-		return
+		return "", "", errors.New("failed to create new private key")
 	}
-	if privKeyPtr[0] == 0 {
+	if privKeyPtr == 0 {
 		return "", "", errors.New("failed to create new private key")
 	}
 
-	// read wasm memory at pointer for the private key string
-	privKey, ok := s.mod.Memory().Read(uint32(privKeyPtr[0]), PRIVATE_KEY_SIZE)
+	// read wasm memory at pointer for the private key string. the key is deallocated later
+	// since it's used for getting the address
+	privKey, ok := s.mod.Memory().Read(uint32(privKeyPtr), PRIVATE_KEY_SIZE)
 	if !ok {
 		return "", "", errors.New("failed to create new private key")
 	}
-	defer s.deallocate.Call(s.ctx, privKeyPtr[0], PRIVATE_KEY_SIZE)
+	defer s.call(s.deallocate, privKeyPtr, PRIVATE_KEY_SIZE)
 
-	// since memory read returns a slice of wasm memory buffer, it needs to be copied
-	// to avoid our returned slice being wiped when wasm memory is wiped.
 	// explicit copy is not needed since we create a string, which copies the slice instead of referencing it
 	key = string(privKey)
 
 	// get public address from the private key, reuse the returned value from private key generation
-	addressPtr, err := s.getAddress.Call(s.ctx, privKeyPtr[0], PRIVATE_KEY_SIZE)
+	addressPtr, err := s.call(s.getAddress, privKeyPtr, PRIVATE_KEY_SIZE)
 	if err != nil {
 		log.Println("get_address error:", err)
 		return "", "", errors.New("failed to get address from the generated private key")
 	}
-	if addressPtr[0] == 0 {
+	if addressPtr == 0 {
 		return "", "", errors.New("internal error when getting address from the generated private key")
 	}
 
 	// read address from wasm memory
-	addr, ok := s.mod.Memory().Read(uint32(addressPtr[0]), ADDRESS_SIZE)
+	address, ok = s.readResultString(uint32(addressPtr), ADDRESS_SIZE)
 	if !ok {
 		return "", "", errors.New("failed to convert generated private key to address")
 	}
-	defer s.deallocate.Call(s.ctx, addressPtr[0], ADDRESS_SIZE)
-
-	// since memory read returns a slice of wasm memory buffer, it needs to be copied
-	// to avoid our returned slice being wiped when wasm memory is wiped.
-	// explicit copy is not needed since we create a string, which copies the slice instead of referencing it
-	address = string(addr)
 
 	return
 }
@@ -144,63 +213,38 @@ func (s *aleoWrapperSession) FormatMessage(message []byte, targetChunks int) (fo
 		}
 	}()
 
-	if targetChunks < 1 || targetChunks > MAX_FORMAT_MESSAGE_CHUNKS {
-		return nil, errors.New("target number of chunks must be between 1 and 32")
+	if err := checkFormatArguments(message, targetChunks); err != nil {
+		return nil, err
 	}
 
-	if len(message) > targetChunks*MESSAGE_FORMAT_BLOCK_SIZE {
-		return nil, fmt.Errorf("target formatted message length must be at most %d (%d chunks)", targetChunks*MESSAGE_FORMAT_BLOCK_SIZE, targetChunks)
-	}
-
-	msgLen := uint64(len(message))
-
-	// allocate memory for the message to pass to the formatting function
-	messagePtr, err := s.allocate.Call(s.ctx, msgLen)
-	if err != nil {
-		log.Println("message allocate error:", err)
+	if err := s.resetArena(len(message)); err != nil {
+		log.Println("arena error:", err)
 		return nil, errors.New("failed to allocate memory for message")
 	}
 
-	// don't forget to dealloc memory
-	defer s.deallocate.Call(s.ctx, messagePtr[0], msgLen)
-
 	// write message to wasm memory
-	ok := s.mod.Memory().Write(uint32(messagePtr[0]), message)
-	if !ok {
+	messagePtr, err := s.putBytes(message)
+	if err != nil {
 		return nil, errors.New("failed to write message to memory for formatting")
 	}
 
 	// call format message with the pointer to the message
-	formatResult, err := s.formatMessage.Call(s.ctx, messagePtr[0], msgLen, uint64(targetChunks))
+	formatResult, err := s.call(s.formatMessage, messagePtr, uint64(len(message)), uint64(targetChunks))
 	if err != nil {
 		log.Println("string format error:", err)
 		return nil, errors.New("failed to format message")
 	}
-	if formatResult[0] == 0 {
+	if formatResult == 0 {
 		return nil, errors.New("invalid message")
 	}
 
-	// take the first (big endian) 32 bits as string size
-	strLen := uint32(formatResult[0] >> 32)
-
-	// casting uint64 to uint32 discards the first (big endian) 32 bits so we're left with the last 32 bits of the result pointer
-	strPtr := uint32(formatResult[0])
-
-	// now we know how many bytes to read to get the string representation of a field
-	buf, ok := s.mod.Memory().Read(strPtr, strLen)
+	// the formatted message is a multiline string, the new lines are dropped while copying it out of wasm memory
+	formattedMessage, ok := s.readResultWithoutNewlines(unpackResult(formatResult))
 	if !ok {
 		return nil, errors.New("failed to convert message to a field")
 	}
-	defer s.deallocate.Call(s.ctx, uint64(strPtr), uint64(strLen))
-
-	// since memory read returns a slice of wasm memory buffer, it needs to be copied
-	// to avoid our returned slice being wiped when wasm memory is wiped
-	formattedMessage = make([]byte, len(buf))
-	copy(formattedMessage, buf)
-
-	adjusted := strings.ReplaceAll(string(formattedMessage), "\n", "")
 
-	return []byte(adjusted), nil
+	return formattedMessage, nil
 }
 
 // Recovers the original byte message from a formatted message string that was created using FormatMessage
@@ -224,59 +268,40 @@ func (s *aleoWrapperSession) RecoverMessage(formattedMessage []byte) (message []
 		}
 	}()
 
-	formattedMsgLen := uint64(len(formattedMessage))
-
-	// allocate memory for the message to pass to the recovery function
-	formattedMessagePtr, err := s.allocate.Call(s.ctx, formattedMsgLen)
-	if err != nil {
-		log.Println("message allocate error:", err)
+	if err := s.resetArena(len(formattedMessage)); err != nil {
+		log.Println("arena error:", err)
 		return nil, errors.New("failed to allocate memory for message")
 	}
 
-	// don't forget to dealloc memory
-	defer s.deallocate.Call(s.ctx, formattedMessagePtr[0], formattedMsgLen)
-
 	// write message to wasm memory
-	ok := s.mod.Memory().Write(uint32(formattedMessagePtr[0]), formattedMessage)
-	if !ok {
+	formattedMessagePtr, err := s.putBytes(formattedMessage)
+	if err != nil {
 		return nil, errors.New("failed to write message to memory for recovering")
 	}
 
 	// call recover message with the pointer to the message
-	recoverResult, err := s.recoverMessage.Call(s.ctx, formattedMessagePtr[0], formattedMsgLen)
+	recoverResult, err := s.call(s.recoverMessage, formattedMessagePtr, uint64(len(formattedMessage)))
 	if err != nil {
 		log.Println("string recover error:", err)
 		return nil, errors.New("failed to recover message")
 	}
-	if recoverResult[0] == 0 {
+	if recoverResult == 0 {
 		return nil, errors.New("invalid message")
 	}
 
-	// take the first (big endian) 32 bits as string size
-	bufLen := uint32(recoverResult[0] >> 32)
-
-	// casting uint64 to uint32 discards the first (big endian) 32 bits so we're left with the last 32 bits of the result pointer
-	bugPtr := uint32(recoverResult[0])
-
-	// now we know how many bytes to read to get the string representation of a field
-	buf, ok := s.mod.Memory().Read(bugPtr, bufLen)
+	message, ok := s.readResult(unpackResult(recoverResult))
 	if !ok {
 		return nil, errors.New("failed to convert message to a field")
 	}
-	defer s.deallocate.Call(s.ctx, uint64(bugPtr), uint64(bufLen))
-
-	// since memory read returns a slice of wasm memory buffer, it needs to be copied
-	// to avoid our returned slice being wiped when wasm memory is wiped
-	message = make([]byte, len(buf))
-	copy(message, buf)
 
-	return
+	return message, nil
 }
 
 // HashMessageToString hashes a message using Poseidon8 Leo function, and returns a string
 // representation of a resulting U128.
 //
-// Use this function if you need a hash as a literal, for example for using it in a contract.
+// Use this function if you need a hash as a literal, for example for using it in a contract. The literal can
+// be parsed with literal.ParseInteger.
 func (s *aleoWrapperSession) HashMessageToString(message []byte) (hash string, err error) {
 	if s.mod == nil || s.mod.IsClosed() {
 		return "", ErrNoModule
@@ -297,57 +322,37 @@ func (s *aleoWrapperSession) HashMessageToString(message []byte) (hash string, e
 		}
 	}()
 
-	msgLen := uint64(len(message))
-
-	// allocate memory for the message to pass to the signing function
-	messagePtr, err := s.allocate.Call(s.ctx, msgLen)
-	if err != nil {
-		log.Println("message allocate error:", err)
+	if err := s.resetArena(len(message)); err != nil {
+		log.Println("arena error:", err)
 		return "", errors.New("failed to allocate memory for message")
 	}
 
-	// don't forget to dealloc memory
-	defer s.deallocate.Call(s.ctx, messagePtr[0], msgLen)
-
 	// write message to wasm memory
-	ok := s.mod.Memory().Write(uint32(messagePtr[0]), message)
-	if !ok {
+	messagePtr, err := s.putBytes(message)
+	if err != nil {
 		return "", errors.New("failed to write message to memory for hashing")
 	}
 
 	// call the hash function and pass the pointer to the message
-	hashResult, err := s.hashMessage.Call(s.ctx, messagePtr[0], msgLen)
+	hashResult, err := s.call(s.hashMessage, messagePtr, uint64(len(message)))
 	if err != nil {
 		log.Println("hash message error:", err)
 		return "", errors.New("failed to hash message to a string representation")
 	}
-	if hashResult[0] == 0 {
+	if hashResult == 0 {
 		return "", errors.New("invalid message")
 	}
 
-	// take the first (big endian) 32 bits as string size
-	hashLen := uint32(hashResult[0] >> 32)
-
-	// casting uint64 to uint32 discards the first (big endian) 32 bits so we're left with the last 32 bits of the result pointer
-	hashPtr := uint32(hashResult[0])
-
-	// now we know how many bytes to read to get the string representation of a field
-	hashBytes, ok := s.mod.Memory().Read(hashPtr, hashLen)
+	hash, ok := s.readResultString(unpackResult(hashResult))
 	if !ok {
 		return "", errors.New("failed to convert message to a field")
 	}
-	defer s.deallocate.Call(s.ctx, uint64(hashPtr), uint64(hashLen))
-
-	// since memory read returns a slice of wasm memory buffer, it needs to be copied
-	// to avoid our returned slice being wiped when wasm memory is wiped.
-	// explicit copy is not needed since we create a string, which copies the slice instead of referencing it
-	hash = string(hashBytes)
 
-	return
+	return hash, nil
 }
 
 // HashMessage hashes a message using Poseidon8 Leo function, and returns a little-endian
-// byte representation of a resulting U128.
+// byte representation of a resulting U128. Use literal.IntegerFromBytesLE to convert it to other forms.
 func (s *aleoWrapperSession) HashMessage(message []byte) (hash []byte, err error) {
 	if s.mod == nil || s.mod.IsClosed() {
 		return nil, ErrNoModule
@@ -368,64 +373,83 @@ func (s *aleoWrapperSession) HashMessage(message []byte) (hash []byte, err error
 		}
 	}()
 
-	msgLen := uint64(len(message))
-
-	// allocate memory for the message to pass to the signing function
-	messagePtr, err := s.allocate.Call(s.ctx, msgLen)
-	if err != nil {
-		log.Println("message allocate error:", err)
+	if err := s.resetArena(len(message)); err != nil {
+		log.Println("arena error:", err)
 		return nil, errors.New("failed to allocate memory for message")
 	}
 
-	// don't forget to dealloc memory
-	defer s.deallocate.Call(s.ctx, messagePtr[0], msgLen)
-
 	// write message to wasm memory
-	ok := s.mod.Memory().Write(uint32(messagePtr[0]), message)
-	if !ok {
+	messagePtr, err := s.putBytes(message)
+	if err != nil {
 		return nil, errors.New("failed to write message to memory for hashing")
 	}
 
 	// pass message to the hash function
-	hashResult, err := s.hashMessageBytes.Call(s.ctx, messagePtr[0], msgLen)
+	hashResult, err := s.call(s.hashMessageBytes, messagePtr, uint64(len(message)))
 	if err != nil {
 		log.Println("hash message bytes error:", err)
 		return nil, errors.New("failed to hash message")
 	}
-	if hashResult[0] == 0 {
+	if hashResult == 0 {
 		return nil, errors.New("invalid message")
 	}
 
-	// take the first (big endian) 32 bits as string size
-	hashLen := uint32(hashResult[0] >> 32)
-
-	// casting uint64 to uint32 discards the first (big endian) 32 bits so we're left with the last 32 bits of the result pointer
-	hashPtr := uint32(hashResult[0])
-
-	// now we know how many bytes to read to get the byte result
-	buf, ok := s.mod.Memory().Read(hashPtr, hashLen)
+	hash, ok := s.readResult(unpackResult(hashResult))
 	if !ok {
 		return nil, errors.New("failed to convert message to a field")
 	}
-	defer s.deallocate.Call(s.ctx, uint64(hashPtr), uint64(hashLen))
 
-	// since memory read returns a slice of wasm memory buffer, it needs to be copied
-	// to avoid our returned slice being wiped when wasm memory is wiped
-	hash = make([]byte, len(buf))
-	copy(hash, buf)
+	return hash, nil
+}
 
-	return
+// SignOptions configures signature creation.
+type SignOptions struct {
+	// SkipSelfCheck disables verification of the created signature, which roughly halves the cost of signing.
+	SkipSelfCheck bool
+	// DeterministicNonce derives the signature nonce from the private key and the message instead of
+	// the randomness source, so the same key and message always produce the same signature.
+	// Requires FeatureDeterministicNonce.
+	DeterministicNonce bool
+}
+
+// flags returns the options as flags of the module signing functions
+func (opts SignOptions) flags() uint64 {
+	var flags uint64
+	if opts.SkipSelfCheck {
+		flags |= signSkipSelfCheck
+	}
+	if opts.DeterministicNonce {
+		flags |= signDeterministicNonce
+	}
+
+	return flags
 }
 
-// Creates a Aleo-compatible Schnorr signature, returns signature's string representation and Aleo-compatible
-// message's string representation.
+// Creates a Aleo-compatible Schnorr signature of a Leo u128 message, returns signature's string representation.
 //
-// The message must be a string or little-endian byte representation of a Leo U128.
+// The message must be the 16 little-endian bytes of the u128, e.g. the result of HashMessage. To sign a u128 literal,
+// e.g. the result of HashMessageToString, convert it with literal.ParseIntegerOfType and Integer.BytesLE.
+//
+// To sign with a key loaded with LoadKey, use SignWithHandle.
+//
+// Sign uses the signing options of the wrapper, see WithSignatureSelfCheck and WithDeterministicNonce.
 func (s *aleoWrapperSession) Sign(key string, message []byte) (signature string, err error) {
+	return s.SignWithOptions(key, message, s.signOptions)
+}
+
+// SignWithOptions is like Sign, but uses the provided options instead of the wrapper ones.
+//
+// If the module doesn't support FeatureSignOptions, the signature is always self checked. If the module doesn't
+// support FeatureDeterministicNonce, requesting a deterministic nonce returns ErrNotSupported.
+func (s *aleoWrapperSession) SignWithOptions(key string, message []byte, opts SignOptions) (signature string, err error) {
 	if s.mod == nil || s.mod.IsClosed() {
 		return "", ErrNoModule
 	}
 
+	if opts.DeterministicNonce && (s.signWithOptions == nil || !s.deterministicNonce) {
+		return "", ErrNotSupported
+	}
+
 	defer func() {
 		if r := recover(); r != nil {
 			// find out exactly what the error was and set err
@@ -444,58 +468,120 @@ func (s *aleoWrapperSession) Sign(key string, message []byte) (signature string,
 	if len(key) != PRIVATE_KEY_SIZE {
 		return "", errors.New("invalid private key size")
 	}
-
-	// allocate memory for the message to pass to the signing function
-	messagePtr, err := s.allocate.Call(s.ctx, uint64(len(message)))
-	if err != nil {
-		log.Println("message allocate error:", err)
-		return "", errors.New("failed to allocate memory for message")
+	if err := codec.ValidatePrivateKey(key); err != nil {
+		return "", err
 	}
-	defer s.deallocate.Call(s.ctx, messagePtr[0], uint64(len(message)))
 
-	// write formatted message to memory
-	ok := s.mod.Memory().Write(uint32(messagePtr[0]), message)
-	if !ok {
-		return "", errors.New("failed to write formatted message to memory for signing")
+	if err := s.resetArena(PRIVATE_KEY_SIZE + len(message)); err != nil {
+		log.Println("arena error:", err)
+		return "", errors.New("failed to allocate memory for signing")
 	}
 
-	// allocate memory for private key to pass to the signing function
-	privateKeyPtr, err := s.allocate.Call(s.ctx, PRIVATE_KEY_SIZE)
+	// write private key to wasm memory
+	privateKeyPtr, err := s.putString(key)
 	if err != nil {
-		log.Println("private key allocate error:", err)
-		return "", errors.New("failed to allocate memory for private key")
+		return "", errors.New("failed to write private key to memory for signing")
 	}
 
-	// don't forget to dealloc memory
-	defer s.deallocate.Call(s.ctx, privateKeyPtr[0], PRIVATE_KEY_SIZE)
-
-	// write private key to wasm memory
-	ok = s.mod.Memory().Write(uint32(privateKeyPtr[0]), []byte(key))
-	if !ok {
-		return "", errors.New("failed to write private key to memory for signing")
+	// write formatted message to memory
+	messagePtr, err := s.putBytes(message)
+	if err != nil {
+		return "", errors.New("failed to write formatted message to memory for signing")
 	}
 
 	// call sign function with the pointers to private key and message
-	signaturePtr, err := s.sign.Call(s.ctx, privateKeyPtr[0], PRIVATE_KEY_SIZE, uint64(messagePtr[0]), uint64(len(message)))
+	var signaturePtr uint64
+	if s.signWithOptions != nil {
+		signaturePtr, err = s.call(s.signWithOptions, privateKeyPtr, PRIVATE_KEY_SIZE, messagePtr, uint64(len(message)), opts.flags())
+	} else {
+		signaturePtr, err = s.call(s.sign, privateKeyPtr, PRIVATE_KEY_SIZE, messagePtr, uint64(len(message)))
+	}
 	if err != nil {
 		log.Println("sign error:", err)
 		return "", errors.New("failed to sign message")
 	}
-	if signaturePtr[0] == 0 {
+	if signaturePtr == 0 {
 		return "", errors.New("internal error when signing message")
 	}
 
 	// read signature string from memory
-	sig, ok := s.mod.Memory().Read(uint32(signaturePtr[0]), SIGNATURE_SIZE)
+	signature, ok := s.readResultString(uint32(signaturePtr), SIGNATURE_SIZE)
 	if !ok {
 		return "", errors.New("failed to sign message")
 	}
-	defer s.deallocate.Call(s.ctx, signaturePtr[0], SIGNATURE_SIZE)
 
-	// since memory read returns a slice of wasm memory buffer, it needs to be copied
-	// to avoid our returned slice being wiped when wasm memory is wiped.
-	// explicit copy is not needed since we create a string, which copies the slice instead of referencing it
-	signature = string(sig)
+	return signature, nil
+}
 
-	return
+// Verify checks an Aleo-compatible Schnorr signature created by Sign against the signer's address.
+//
+// The message is interpreted the same way as in Sign. Verify returns an error only if the check couldn't
+// be performed, an invalid signature is reported with valid = false. If the module doesn't support FeatureVerify,
+// the signature is checked in pure Go with VerifySignature.
+func (s *aleoWrapperSession) Verify(address string, message []byte, signature string) (valid bool, err error) {
+	if s.mod == nil || s.mod.IsClosed() {
+		return false, ErrNoModule
+	}
+
+	if s.verify == nil {
+		return VerifySignature(address, message, signature)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// find out exactly what the error was and set err
+			switch x := r.(type) {
+			case string:
+				err = errors.New(x)
+			case error:
+				err = x
+			default:
+				err = errors.New("unknown panic")
+			}
+			valid = false
+		}
+	}()
+
+	if len(address) != ADDRESS_SIZE {
+		return false, errors.New("invalid address size")
+	}
+	if err := codec.ValidateAddress(address); err != nil {
+		return false, err
+	}
+
+	if len(signature) != SIGNATURE_SIZE {
+		return false, errors.New("invalid signature size")
+	}
+
+	if err := s.resetArena(ADDRESS_SIZE + len(message) + SIGNATURE_SIZE); err != nil {
+		log.Println("arena error:", err)
+		return false, errors.New("failed to allocate memory for verification")
+	}
+
+	// write address to wasm memory
+	addressPtr, err := s.putString(address)
+	if err != nil {
+		return false, errors.New("failed to write address to memory for verification")
+	}
+
+	// write message to wasm memory
+	messagePtr, err := s.putBytes(message)
+	if err != nil {
+		return false, errors.New("failed to write message to memory for verification")
+	}
+
+	// write signature to wasm memory
+	signaturePtr, err := s.putString(signature)
+	if err != nil {
+		return false, errors.New("failed to write signature to memory for verification")
+	}
+
+	// call verify function with the pointers to address, message and signature
+	result, err := s.call(s.verify, addressPtr, ADDRESS_SIZE, messagePtr, uint64(len(message)), signaturePtr, SIGNATURE_SIZE)
+	if err != nil {
+		log.Println("verify error:", err)
+		return false, errors.New("failed to verify signature")
+	}
+
+	return result == 1, nil
 }
@@ -0,0 +1,80 @@
+package aleo_utils
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	// size of the report header prepended by the enclave runtime (Open Enclave/EGo) to the SGX quote
+	sgxReportHeaderSize = 16
+	// size of the SGX quote header, which precedes the report body
+	sgxQuoteHeaderSize = 48
+	// size of the SGX report body
+	sgxReportBodySize = 384
+
+	sgxReportTypeRemote = 2
+)
+
+// offsets of the fields inside of the SGX report body
+const (
+	sgxBodyAttributesOffset = 48
+	sgxBodyMrEnclaveOffset  = 64
+	sgxBodyMrSignerOffset   = 128
+	sgxBodyProductIDOffset  = 256
+	sgxBodySvnOffset        = 258
+	sgxBodyReportDataOffset = 320
+)
+
+// SGXReport contains the fields of an SGX remote report, which are relevant for checking the enclave identity.
+type SGXReport struct {
+	// Quote is the raw SGX quote, which follows the report header
+	Quote []byte
+
+	Attributes [16]byte
+	// UniqueID is the MRENCLAVE value of the enclave
+	UniqueID [32]byte
+	// SignerID is the MRSIGNER value of the enclave
+	SignerID [32]byte
+	// ProductID is the little-endian ISV product ID, padded to 16 bytes the same way EGo reports it
+	ProductID       [16]byte
+	SecurityVersion uint16
+	// Data is the user data embedded into the report by the enclave
+	Data [64]byte
+}
+
+// ParseSGXReport parses a remote report, as returned by EGo's enclave.GetRemoteReport.
+//
+// ParseSGXReport doesn't check the quote signature, use a QuoteVerifier for that.
+func ParseSGXReport(report []byte) (*SGXReport, error) {
+	if len(report) < sgxReportHeaderSize+sgxQuoteHeaderSize+sgxReportBodySize {
+		return nil, errors.New("report is too short")
+	}
+
+	reportType := binary.LittleEndian.Uint32(report[4:8])
+	if reportType != sgxReportTypeRemote {
+		return nil, fmt.Errorf("unexpected report type %d, expected remote report", reportType)
+	}
+
+	quoteSize := binary.LittleEndian.Uint64(report[8:16])
+	if quoteSize != uint64(len(report)-sgxReportHeaderSize) {
+		return nil, errors.New("report size doesn't match the header")
+	}
+
+	quote := report[sgxReportHeaderSize:]
+	body := quote[sgxQuoteHeaderSize : sgxQuoteHeaderSize+sgxReportBodySize]
+
+	result := &SGXReport{
+		Quote:           quote,
+		SecurityVersion: binary.LittleEndian.Uint16(body[sgxBodySvnOffset:]),
+	}
+
+	copy(result.Attributes[:], body[sgxBodyAttributesOffset:])
+	copy(result.UniqueID[:], body[sgxBodyMrEnclaveOffset:])
+	copy(result.SignerID[:], body[sgxBodyMrSignerOffset:])
+	copy(result.ProductID[:2], body[sgxBodyProductIDOffset:])
+	copy(result.Data[:], body[sgxBodyReportDataOffset:])
+
+	return result, nil
+}
@@ -0,0 +1,206 @@
+package aleo_utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/zkportal/aleo-utils-go/codec"
+	"github.com/zkportal/aleo-utils-go/field"
+	"github.com/zkportal/aleo-utils-go/literal"
+	"github.com/zkportal/aleo-utils-go/poseidon"
+)
+
+const (
+	// size of a scalar or a field element in bytes
+	fieldElementSize = codec.FieldSize
+	// size of a signature payload: challenge, response and compute key
+	signaturePayloadSize = codec.SignatureSize
+	// size of a signed message, the little-endian bytes of a u128
+	signedMessageSize = 16
+)
+
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// ComputeKey is the compute key of a signer, which is embedded into every signature.
+type ComputeKey struct {
+	// PkSig is the little-endian x-coordinate of the public key of the signer
+	PkSig [fieldElementSize]byte
+	// PrSig is the little-endian x-coordinate of the public randomizer of the signer
+	PrSig [fieldElementSize]byte
+}
+
+// Signature is a parsed Aleo Schnorr signature, as created by Session.Sign.
+type Signature struct {
+	// Challenge is the little-endian challenge scalar
+	Challenge [fieldElementSize]byte
+	// Response is the little-endian response scalar
+	Response   [fieldElementSize]byte
+	ComputeKey ComputeKey
+}
+
+// ParseSignature decodes a `sign1...` signature string and validates its components: the scalars must be
+// in the scalar field, and the compute key coordinates must belong to points of the prime-order subgroup.
+//
+// ParseSignature doesn't check that the signature is valid for any message, use Session.Verify for that.
+func ParseSignature(signature string) (*Signature, error) {
+	if len(signature) != SIGNATURE_SIZE {
+		return nil, fmt.Errorf("%w: unexpected size %d", ErrInvalidSignature, len(signature))
+	}
+
+	payload, err := codec.DecodeBech32m(signature, codec.SignaturePrefix, signaturePayloadSize)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	sig := new(Signature)
+	copy(sig.Challenge[:], payload[0:])
+	copy(sig.Response[:], payload[fieldElementSize:])
+	copy(sig.ComputeKey.PkSig[:], payload[2*fieldElementSize:])
+	copy(sig.ComputeKey.PrSig[:], payload[3*fieldElementSize:])
+
+	if err := sig.validate(); err != nil {
+		return nil, err
+	}
+
+	return sig, nil
+}
+
+func (sig *Signature) validate() error {
+	if _, err := literal.ScalarFromBytesLE(sig.Challenge[:]); err != nil {
+		return fmt.Errorf("%w: challenge is out of the scalar field", ErrInvalidSignature)
+	}
+
+	if _, err := literal.ScalarFromBytesLE(sig.Response[:]); err != nil {
+		return fmt.Errorf("%w: response is out of the scalar field", ErrInvalidSignature)
+	}
+
+	if _, err := literal.GroupFromBytesLE(sig.ComputeKey.PkSig[:]); err != nil {
+		return fmt.Errorf("%w: invalid public key in compute key", ErrInvalidSignature)
+	}
+
+	if _, err := literal.GroupFromBytesLE(sig.ComputeKey.PrSig[:]); err != nil {
+		return fmt.Errorf("%w: invalid public randomizer in compute key", ErrInvalidSignature)
+	}
+
+	return nil
+}
+
+// Bytes returns the signature payload in the snarkVM byte format: challenge, response and compute key.
+func (sig *Signature) Bytes() []byte {
+	payload := make([]byte, 0, signaturePayloadSize)
+	payload = append(payload, sig.Challenge[:]...)
+	payload = append(payload, sig.Response[:]...)
+	payload = append(payload, sig.ComputeKey.PkSig[:]...)
+	payload = append(payload, sig.ComputeKey.PrSig[:]...)
+
+	return payload
+}
+
+// String encodes the signature to the `sign1...` string form.
+func (sig *Signature) String() string {
+	return codec.Bech32mEncode(codec.SignaturePrefix, sig.Bytes())
+}
+
+// baseField converts little-endian bytes of a validated coordinate to a base field element
+func baseField(buf [fieldElementSize]byte) field.Base {
+	var f field.Base
+	if _, err := f.SetBytesLE(buf[:]); err != nil {
+		// unreachable for validated signatures
+		panic(err)
+	}
+
+	return f
+}
+
+// Address returns the address of the compute key owner: pk_sig + pr_sig + G * HashToScalarPSD4(pk_sig.x, pr_sig.x),
+// the same as snarkVM's ComputeKey::to_address. The compute key must be validated, e.g. with ParseSignature.
+func (ck *ComputeKey) Address() string {
+	pkSig, prSig := literal.Group(ck.PkSig), literal.Group(ck.PrSig)
+
+	skPrf := poseidon.HashToScalar4([]field.Base{baseField(ck.PkSig), baseField(ck.PrSig)})
+	skPrfScalar, err := literal.ScalarFromBytesLE(skPrf.BytesLE())
+	if err != nil {
+		// unreachable, the hash is truncated to the scalar field capacity
+		panic(err)
+	}
+
+	address := pkSig.Add(prSig).Add(literal.Generator().Mul(skPrfScalar))
+
+	return literal.NewAddress(address).String()
+}
+
+// Address returns the address of the signer, derived from the compute key of the signature.
+func (sig *Signature) Address() string {
+	return sig.ComputeKey.Address()
+}
+
+// SignatureAddress parses a `sign1...` signature with ParseSignature and returns the address of the signer, derived
+// from the compute key of the signature. It doesn't need the WASM module.
+func SignatureAddress(signature string) (address string, err error) {
+	sig, err := ParseSignature(signature)
+	if err != nil {
+		return "", err
+	}
+
+	return sig.Address(), nil
+}
+
+// signedMessageFields converts a message to the fields which Sign signs: the first 16 bytes are read as
+// a little-endian u128, which is encoded as a Leo plaintext literal
+func signedMessageFields(message []byte) ([]field.Base, error) {
+	if len(message) < signedMessageSize {
+		return nil, fmt.Errorf("message needs at least %d bytes, got %d", signedMessageSize, len(message))
+	}
+
+	number, err := literal.IntegerFromBytesLE(literal.U128, message[:signedMessageSize])
+	if err != nil {
+		return nil, err
+	}
+
+	return poseidon.PlaintextFields(number.String())
+}
+
+// VerifySignature checks an Aleo Schnorr signature created by Session.Sign against the signer's address in pure Go,
+// the same way as snarkVM's Signature::verify. It doesn't need the WASM module.
+//
+// The message is interpreted the same way as in Sign. VerifySignature returns an error only if the address,
+// the message or the signature can't be decoded, an invalid signature is reported with valid = false.
+func VerifySignature(address string, message []byte, signature string) (valid bool, err error) {
+	addressPoint, err := literal.ParseAddress(address)
+	if err != nil {
+		return false, err
+	}
+
+	sig, err := ParseSignature(signature)
+	if err != nil {
+		return false, err
+	}
+
+	fields, err := signedMessageFields(message)
+	if err != nil {
+		return false, err
+	}
+
+	challenge, response := literal.Scalar(sig.Challenge), literal.Scalar(sig.Response)
+	pkSig := literal.Group(sig.ComputeKey.PkSig)
+
+	// g_r = G * response + pk_sig * challenge
+	gR := literal.Generator().Mul(response).Add(pkSig.Mul(challenge))
+
+	preimage := make([]field.Base, 0, 4+len(fields))
+	preimage = append(preimage,
+		baseField(gR),
+		baseField(sig.ComputeKey.PkSig),
+		baseField(sig.ComputeKey.PrSig),
+		baseField(addressPoint),
+	)
+	preimage = append(preimage, fields...)
+
+	candidate := poseidon.HashToScalar8(preimage)
+	if !bytes.Equal(candidate.BytesLE(), sig.Challenge[:]) {
+		return false, nil
+	}
+
+	return sig.Address() == address, nil
+}
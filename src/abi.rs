@@ -0,0 +1,38 @@
+use alloc::string::String;
+
+use crate::{
+  memory::forget_buf_ptr_len,
+  network::NETWORK_NAME,
+};
+
+// Version of the host-guest interface. Increment when an exported function changes its signature or semantics.
+pub const ABI_VERSION: u32 = 1;
+
+const HASH_ALGORITHMS: &[&str] = &["poseidon8"];
+const SIGNING_MODES: &[&str] = &["schnorr"];
+const FEATURES: &[&str] = &["verify", "format_and_hash", "batch", "key_handles", "sign_options", "deterministic_nonce", "records", "record_encryption", "record_serial_numbers", "transitions", "programs", "requests", "fee_authorizations", "transactions", "transaction_checks"];
+
+#[no_mangle]
+pub extern "C" fn abi_version() -> u32 {
+  ABI_VERSION
+}
+
+fn push_capability(caps: &mut String, key: &str, values: &[&str]) {
+  caps.push_str(key);
+  caps.push('=');
+  caps.push_str(values.join(",").as_str());
+  caps.push('\n');
+}
+
+// Returns a list of module capabilities as lines of `key=value1,value2`
+#[no_mangle]
+pub extern "C" fn capabilities() -> u64 {
+  let mut caps = String::new();
+
+  push_capability(&mut caps, "networks", &[NETWORK_NAME]);
+  push_capability(&mut caps, "hash", HASH_ALGORITHMS);
+  push_capability(&mut caps, "signing", SIGNING_MODES);
+  push_capability(&mut caps, "features", FEATURES);
+
+  forget_buf_ptr_len(caps.into_bytes())
+}
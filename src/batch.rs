@@ -0,0 +1,134 @@
+use core::{str, slice};
+use alloc::{vec::Vec, string::ToString};
+
+use snarkvm_console::{
+  program::{Value, U128},
+  prelude::{FromBytes, FromStr, ToBytes},
+};
+
+use crate::{
+  hash::hash_value,
+  log::log,
+  memory::{forget_bufs_ptr_len, unpack_bufs},
+  network::CurrentNetwork,
+  sign::{read_private_key, private_key_address, sign_u128, SIGN_SKIP_SELF_CHECK, SIGN_DETERMINISTIC_NONCE},
+};
+
+// Every batch item result starts with a status byte, followed by the result on success
+const ITEM_OK: u8 = 1;
+const ITEM_FAILED: u8 = 0;
+
+fn item_result(result: Option<Vec<u8>>) -> Vec<u8> {
+  match result {
+    Some(payload) => {
+      let mut item = Vec::with_capacity(1 + payload.len());
+      item.push(ITEM_OK);
+      item.extend_from_slice(&payload);
+      item
+    },
+    None => Vec::from([ITEM_FAILED]),
+  }
+}
+
+fn hash_message_item(message: &[u8]) -> Option<Vec<u8>> {
+  let message_str = match str::from_utf8(message) {
+    Ok(val) => val,
+    Err(e) => {
+      let mut err_str = String::from("failed to rebuild message: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return None;
+    }
+  };
+
+  let value = match Value::<CurrentNetwork>::from_str(message_str) {
+    Ok(val) => val,
+    Err(e) => {
+      let mut err_str = String::from("failed to parse value from string: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return None;
+    }
+  };
+
+  let hash = hash_value(&value)?;
+
+  hash.to_bytes_le().ok()
+}
+
+// Hashes every message in a packed buffer the same way as hash_message_bytes. Returns a packed buffer
+// of item results.
+#[no_mangle]
+pub extern "C" fn hash_message_batch(messages: *const u8, messages_len: usize) -> u64 {
+  let packed = unsafe {
+    slice::from_raw_parts(messages, messages_len)
+  };
+
+  let messages = match unpack_bufs(packed) {
+    Some(val) => val,
+    None => {
+      log("malformed message batch");
+      return 0;
+    }
+  };
+
+  let results: Vec<Vec<u8>> = messages.iter()
+    .map(|message| item_result(hash_message_item(message)))
+    .collect();
+
+  let result_refs: Vec<&[u8]> = results.iter().map(|result| result.as_slice()).collect();
+
+  forget_bufs_ptr_len(&result_refs)
+}
+
+// Signs every message in a packed buffer the same way as sign_with_options, parsing the private key only once.
+// Returns a packed buffer of item results.
+#[no_mangle]
+pub extern "C" fn sign_batch(private_key_str: *const u8, private_key_len: usize, messages: *const u8, messages_len: usize, flags: u32) -> u64 {
+  let priv_key = match read_private_key(private_key_str, private_key_len) {
+    Some(pk) => pk,
+    None => return 0,
+  };
+
+  // get the public key of the private key for self verification
+  let addr = if flags & SIGN_SKIP_SELF_CHECK == 0 {
+    match private_key_address(&priv_key) {
+      Some(val) => Some(val),
+      None => return 0,
+    }
+  } else {
+    None
+  };
+  let deterministic = flags & SIGN_DETERMINISTIC_NONCE != 0;
+
+  let packed = unsafe {
+    slice::from_raw_parts(messages, messages_len)
+  };
+
+  let messages = match unpack_bufs(packed) {
+    Some(val) => val,
+    None => {
+      log("malformed message batch");
+      return 0;
+    }
+  };
+
+  let results: Vec<Vec<u8>> = messages.iter()
+    .map(|message| {
+      let signature = U128::<CurrentNetwork>::from_bytes_le(message)
+        .ok()
+        .and_then(|number| sign_u128(&priv_key, addr.as_ref(), number, deterministic))
+        .map(|signature| signature.to_string().into_bytes());
+
+      item_result(signature)
+    })
+    .collect();
+
+  let result_refs: Vec<&[u8]> = results.iter().map(|result| result.as_slice()).collect();
+
+  forget_bufs_ptr_len(&result_refs)
+}
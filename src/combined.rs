@@ -0,0 +1,104 @@
+use core::slice;
+use alloc::string::ToString;
+
+use snarkvm_console::prelude::ToBytes;
+
+use crate::{
+  format::format_message_value,
+  hash::hash_value,
+  log::log,
+  memory::forget_bufs_ptr_len,
+  sign::{read_private_key, private_key_address, sign_u128, SIGN_SKIP_SELF_CHECK, SIGN_DETERMINISTIC_NONCE},
+};
+
+// Formats a message and hashes the formatted value without printing and parsing it again.
+// Returns the formatted message string, the hash as a u128 literal and the hash as LE bytes.
+#[no_mangle]
+pub extern "C" fn format_and_hash(message: *const u8, message_len: usize, target_chunks: usize) -> u64 {
+  let message_bytes = unsafe {
+    slice::from_raw_parts(message, message_len)
+  };
+
+  let value = match format_message_value(message_bytes, target_chunks) {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let hash = match hash_value(&value) {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let hash_bytes = match hash.to_bytes_le() {
+    Ok(val) => val,
+    Err(e) => {
+      let mut err_str = String::from("failed to convert hash value to bytes: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return 0;
+    }
+  };
+
+  let formatted_message = value.to_string();
+  let hash_literal = hash.to_string();
+
+  forget_bufs_ptr_len(&[formatted_message.as_bytes(), hash_literal.as_bytes(), hash_bytes.as_slice()])
+}
+
+// Formats a message, hashes it and signs the hash bytes the same way as sign_with_options does.
+// Returns the formatted message string, the hash as a u128 literal, the hash as LE bytes, and the signature.
+#[no_mangle]
+pub extern "C" fn format_hash_sign(private_key_str: *const u8, private_key_len: usize, message: *const u8, message_len: usize, target_chunks: usize, flags: u32) -> u64 {
+  let priv_key = match read_private_key(private_key_str, private_key_len) {
+    Some(pk) => pk,
+    None => return 0,
+  };
+
+  // get the public key of the private key for self verification
+  let addr = if flags & SIGN_SKIP_SELF_CHECK == 0 {
+    match private_key_address(&priv_key) {
+      Some(val) => Some(val),
+      None => return 0,
+    }
+  } else {
+    None
+  };
+
+  let message_bytes = unsafe {
+    slice::from_raw_parts(message, message_len)
+  };
+
+  let value = match format_message_value(message_bytes, target_chunks) {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let hash = match hash_value(&value) {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let hash_bytes = match hash.to_bytes_le() {
+    Ok(val) => val,
+    Err(e) => {
+      let mut err_str = String::from("failed to convert hash value to bytes: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return 0;
+    }
+  };
+
+  let signature = match sign_u128(&priv_key, addr.as_ref(), hash, flags & SIGN_DETERMINISTIC_NONCE != 0) {
+    Some(sig) => sig.to_string(),
+    None => return 0,
+  };
+
+  let formatted_message = value.to_string();
+  let hash_literal = hash.to_string();
+
+  forget_bufs_ptr_len(&[formatted_message.as_bytes(), hash_literal.as_bytes(), hash_bytes.as_slice(), signature.as_bytes()])
+}
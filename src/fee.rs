@@ -0,0 +1,68 @@
+use core::cell::RefCell;
+use alloc::string::ToString;
+
+use snarkvm_console::{
+  account::PrivateKey,
+  prelude::bail,
+  program::Field,
+};
+use snarkvm_synthesizer_process::Process;
+use rand::{rngs::StdRng, SeedableRng};
+
+use crate::{
+  log::log,
+  memory::{forget_buf_ptr_len, read_value},
+  network::{CurrentAleo, CurrentNetwork},
+};
+
+thread_local! {
+  // The process with the credits.aleo program, loaded by the first fee authorization. The circuit keys aren't
+  // loaded, as authorizing a function only evaluates it without proving.
+  static PROCESS: RefCell<Option<Process<CurrentNetwork>>> = RefCell::new(None);
+}
+
+// Authorizes a credits.aleo/fee_public call, which pays the base fee and the priority fee in microcredits for
+// the execution or deployment with the ID. The request is signed and evaluated to build the fee transition.
+// Returns the authorization serialized as JSON, with the signed request and the fee transition.
+#[no_mangle]
+pub extern "C" fn authorize_fee_public(
+  private_key_str: *const u8, private_key_len: usize,
+  execution_id_str: *const u8, execution_id_len: usize,
+  base_fee: u64, priority_fee: u64,
+) -> u64 {
+  let private_key: PrivateKey<CurrentNetwork> = match read_value(private_key_str, private_key_len, "private key") {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let execution_id: Field<CurrentNetwork> = match read_value(execution_id_str, execution_id_len, "execution ID") {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let result = PROCESS.with(|process| {
+    let mut process = process.borrow_mut();
+    if process.is_none() {
+      *process = Some(Process::load_web()?);
+    }
+
+    match process.as_ref() {
+      Some(process) => process.authorize_fee_public::<CurrentAleo, _>(&private_key, base_fee, priority_fee, execution_id, &mut StdRng::from_entropy()),
+      None => bail!("credits.aleo process is not loaded"),
+    }
+  });
+
+  let authorization = match result {
+    Ok(val) => val,
+    Err(e) => {
+      let mut err_str = String::from("failed to authorize fee: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return 0;
+    }
+  };
+
+  forget_buf_ptr_len(authorization.to_string().into_bytes())
+}
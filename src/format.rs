@@ -23,23 +23,18 @@ fn create_struct_key(prefix: &str, idx: usize) -> String {
   key
 }
 
-#[no_mangle]
-pub extern "C" fn format_message(message: *const u8, message_len: usize, target_chunks: usize) -> u64 {
+// Builds a Leo struct of target_chunks structs of 32 u128 numbers from the message bytes
+pub fn format_message_value(message_bytes: &[u8], target_chunks: usize) -> Option<Value<CurrentNetwork>> {
   if target_chunks < 1 || target_chunks > MAX_CHUNKS {
     log("number of chunks must be between 1 and 32");
-    return 0;
+    return None;
   }
 
-  if message_len > CHUNK_SIZE * target_chunks {
+  if message_bytes.len() > CHUNK_SIZE * target_chunks {
     log("message is too big to fit into specified number of chunks");
-    return 0;
+    return None;
   }
 
-  // Convert a pointer to a string into a string
-  let message_bytes = unsafe {
-    slice::from_raw_parts(message, message_len)
-  };
-
   let mut buf = Vec::<u8>::with_capacity(target_chunks * CHUNK_SIZE);
   buf.extend_from_slice(message_bytes);
 
@@ -56,7 +51,7 @@ pub extern "C" fn format_message(message: *const u8, message_len: usize, target_
     .collect();
 
   if !numbers.is_ok() {
-    return 0;
+    return None;
   }
 
   let numeric_message = numbers.unwrap().iter()
@@ -89,7 +84,21 @@ pub extern "C" fn format_message(message: *const u8, message_len: usize, target_
   }
 
   // build final value
-  let value = Value::Plaintext(Plaintext::Struct(data_map, Default::default()));
+  Some(Value::Plaintext(Plaintext::Struct(data_map, Default::default())))
+}
+
+#[no_mangle]
+pub extern "C" fn format_message(message: *const u8, message_len: usize, target_chunks: usize) -> u64 {
+  // Convert a pointer to a string into a string
+  let message_bytes = unsafe {
+    slice::from_raw_parts(message, message_len)
+  };
+
+  let value = match format_message_value(message_bytes, target_chunks) {
+    Some(val) => val,
+    None => return 0,
+  };
+
   let output_str = value.to_string();
   let output_bytes = output_str.into_bytes();
 
@@ -12,6 +12,37 @@ use crate::{
   network::CurrentNetwork,
 };
 
+// Computes Poseidon8 hash of a Leo value and casts it to U128 the same way Leo does
+pub fn hash_value(value: &Value<CurrentNetwork>) -> Option<U128<CurrentNetwork>> {
+  // convert the value into an array of fields
+  let fields = match value.to_fields() {
+    Ok(val) => val,
+    Err(e) => {
+      let mut err_str = String::from("failed to convert value to fields: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return None;
+    }
+  };
+
+  // hash the fields
+  let hash = match CurrentNetwork::hash_psd8(fields.as_slice()) {
+    Ok(val) => val,
+    Err(e) => {
+      let mut err_str = String::from("failed to compute Poseidon8 hash: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return None;
+    }
+  };
+
+  Some(hash.cast_lossy())
+}
+
 #[no_mangle]
 pub extern "C" fn hash_message(message: *const u8, message_len: usize) -> u64 {
   // Convert a pointer to a string into a string
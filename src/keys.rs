@@ -0,0 +1,155 @@
+use core::{cell::RefCell, mem, ptr, slice, sync::atomic::{compiler_fence, Ordering}};
+use alloc::string::ToString;
+
+use snarkvm_console::{
+  account::{Address, PrivateKey},
+  prelude::FromBytes,
+  program::U128,
+};
+
+use crate::{
+  log::log,
+  memory::forget_buf_ptr,
+  network::CurrentNetwork,
+  sign::{read_private_key, private_key_address, sign_u128, SIGN_SKIP_SELF_CHECK, SIGN_DETERMINISTIC_NONCE},
+};
+
+// Maximal number of keys loaded at the same time
+const MAX_KEYS: usize = 64;
+
+// A private key parsed once and kept inside of the module instance
+struct LoadedKey {
+  private_key: PrivateKey<CurrentNetwork>,
+  address: Address<CurrentNetwork>,
+}
+
+thread_local! {
+  // Loaded keys, a key handle is an index in this list plus one, so that 0 is never a valid handle. The storage has
+  // a fixed capacity, so it's never reallocated and keys are never copied to another place in memory.
+  static KEYS: RefCell<[Option<LoadedKey>; MAX_KEYS]> = RefCell::new(core::array::from_fn(|_| None));
+}
+
+// Overwrites the key in the slot with zeroes in place and marks the slot as free. The key doesn't own heap memory,
+// so it doesn't need to be dropped.
+fn zero_slot(slot: &mut Option<LoadedKey>) -> bool {
+  let key = match slot.as_mut() {
+    Some(key) => key,
+    None => return false,
+  };
+
+  unsafe {
+    let key_ptr = key as *mut LoadedKey as *mut u8;
+    for i in 0..mem::size_of::<LoadedKey>() {
+      ptr::write_volatile(key_ptr.add(i), 0);
+    }
+    compiler_fence(Ordering::SeqCst);
+
+    // the zeroed bytes aren't a valid key, so the slot is overwritten without dropping it
+    ptr::write(slot, None);
+  }
+
+  true
+}
+
+#[no_mangle]
+pub extern "C" fn load_key(private_key_str: *const u8, private_key_len: usize) -> u32 {
+  let private_key = match read_private_key(private_key_str, private_key_len) {
+    Some(pk) => pk,
+    None => return 0,
+  };
+
+  let address = match private_key_address(&private_key) {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  KEYS.with(|keys| {
+    let mut keys = keys.borrow_mut();
+
+    match keys.iter().position(|slot| slot.is_none()) {
+      Some(idx) => {
+        keys[idx] = Some(LoadedKey { private_key, address });
+        (idx + 1) as u32
+      },
+      None => {
+        log("too many loaded keys");
+        0
+      }
+    }
+  })
+}
+
+#[no_mangle]
+pub extern "C" fn release_key(handle: u32) -> u32 {
+  KEYS.with(|keys| {
+    let mut keys = keys.borrow_mut();
+
+    let slot = match (handle as usize).checked_sub(1).and_then(|idx| keys.get_mut(idx)) {
+      Some(slot) => slot,
+      None => {
+        log("invalid key handle");
+        return 0;
+      }
+    };
+
+    if !zero_slot(slot) {
+      log("key handle is already released");
+      return 0;
+    }
+
+    1
+  })
+}
+
+#[no_mangle]
+pub extern "C" fn release_all_keys() {
+  KEYS.with(|keys| {
+    let mut keys = keys.borrow_mut();
+
+    for slot in keys.iter_mut() {
+      zero_slot(slot);
+    }
+  })
+}
+
+#[no_mangle]
+pub extern "C" fn sign_with_key(handle: u32, hash_field_str: *const u8, hash_field_len: usize, flags: u32) -> *const u8 {
+  // restore the data for signing slice from the pointer
+  let hash_field_bytes = unsafe {
+    slice::from_raw_parts(hash_field_str, hash_field_len)
+  };
+
+  // the message is interpreted the same way as in sign
+  let number = match U128::<CurrentNetwork>::from_bytes_le(hash_field_bytes) {
+    Ok(val) => val,
+    Err(e) => {
+      let mut err_str = String::from("failed to parse u128 plaintext value from bytes: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return ptr::null();
+    },
+  };
+
+  let signature = KEYS.with(|keys| {
+    let keys = keys.borrow();
+
+    match (handle as usize).checked_sub(1).and_then(|idx| keys.get(idx)).and_then(|slot| slot.as_ref()) {
+      Some(key) => {
+        // the address is already derived, so the self check only costs the verification
+        let addr = if flags & SIGN_SKIP_SELF_CHECK == 0 { Some(&key.address) } else { None };
+        sign_u128(&key.private_key, addr, number, flags & SIGN_DETERMINISTIC_NONCE != 0)
+      },
+      None => {
+        log("invalid key handle");
+        None
+      }
+    }
+  });
+
+  match signature {
+    Some(sig) => forget_buf_ptr(sig.to_string().into_bytes()),
+    None => ptr::null(),
+  }
+}
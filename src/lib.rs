@@ -6,11 +6,22 @@
 extern crate alloc;
 extern crate core;
 
+pub mod abi;
 pub mod memory;
 pub mod format;
 pub mod log;
 pub mod key;
 pub mod hash;
 pub mod sign;
+pub mod combined;
+pub mod batch;
+pub mod keys;
+pub mod record;
+pub mod transition;
+pub mod program;
+pub mod request;
+pub mod fee;
+pub mod transaction;
+pub mod version;
 
 mod network;
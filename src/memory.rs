@@ -1,5 +1,7 @@
-use alloc::vec::Vec;
-use core::mem;
+use alloc::{vec::Vec, string::ToString};
+use core::{mem, slice, str, str::FromStr};
+
+use crate::log::log;
 
 pub fn forget_buf_ptr(buf: Vec<u8>) -> *const u8 {
   let ptr = buf.as_ptr();
@@ -17,6 +19,112 @@ pub fn forget_buf_ptr_len(buf: Vec<u8>) -> u64 {
   (len << 32) | output_ptr
 }
 
+// Packs multiple buffers into one, each buffer is prefixed with its length as 4 little-endian bytes.
+pub fn forget_bufs_ptr_len(bufs: &[&[u8]]) -> u64 {
+  let total_len = bufs.iter().map(|buf| 4 + buf.len()).sum();
+  let mut output = Vec::<u8>::with_capacity(total_len);
+
+  for buf in bufs {
+    output.extend_from_slice(&(buf.len() as u32).to_le_bytes());
+    output.extend_from_slice(buf);
+  }
+
+  forget_buf_ptr_len(output)
+}
+
+// Splits a buffer created by the host, which packs multiple buffers each prefixed with its length
+// as 4 little-endian bytes. Returns None if the buffer is malformed.
+pub fn unpack_bufs<'a>(packed: &'a [u8]) -> Option<Vec<&'a [u8]>> {
+  let mut bufs = Vec::new();
+  let mut rest = packed;
+
+  while !rest.is_empty() {
+    if rest.len() < 4 {
+      return None;
+    }
+
+    let (len_bytes, tail) = rest.split_at(4);
+    let len = u32::from_le_bytes([len_bytes[0], len_bytes[1], len_bytes[2], len_bytes[3]]) as usize;
+    if len > tail.len() {
+      return None;
+    }
+
+    let (buf, tail) = tail.split_at(len);
+    bufs.push(buf);
+    rest = tail;
+  }
+
+  Some(bufs)
+}
+
+// Restores a string from a pointer written by the host. The name is used in the error message.
+pub fn read_str<'a>(ptr: *const u8, len: usize, name: &str) -> Option<&'a str> {
+  unsafe {
+    match str::from_utf8(slice::from_raw_parts(ptr, len)) {
+      Ok(val) => Some(val),
+      Err(e) => {
+        let mut err_str = String::from("failed to rebuild ");
+        err_str.push_str(name);
+        err_str.push_str(" string from pointer: ");
+        err_str.push_str(e.to_string().as_str());
+
+        log(err_str);
+
+        None
+      }
+    }
+  }
+}
+
+// Logs a parsing error of a string argument
+pub fn log_parse_error<E: ToString>(name: &str, e: E) {
+  let mut err_str = String::from("failed to parse ");
+  err_str.push_str(name);
+  err_str.push_str(" from string: ");
+  err_str.push_str(e.to_string().as_str());
+
+  log(err_str);
+}
+
+// Restores a value, which implements FromStr, from a string pointer
+pub fn read_value<T: FromStr>(ptr: *const u8, len: usize, name: &str) -> Option<T> where T::Err: ToString {
+  let value_str = read_str(ptr, len, name)?;
+
+  match T::from_str(value_str) {
+    Ok(val) => Some(val),
+    Err(e) => {
+      log_parse_error(name, e);
+      None
+    }
+  }
+}
+
+// Restores a list of values from a packed buffer of strings written by the host
+pub fn read_packed_values<T: FromStr>(ptr: *const u8, len: usize, name: &str) -> Option<Vec<T>> where T::Err: ToString {
+  let packed = unsafe {
+    slice::from_raw_parts(ptr, len)
+  };
+
+  let bufs = match unpack_bufs(packed) {
+    Some(val) => val,
+    None => {
+      let mut err_str = String::from("malformed packed ");
+      err_str.push_str(name);
+
+      log(err_str);
+
+      return None;
+    }
+  };
+
+  let mut values = Vec::with_capacity(bufs.len());
+  for buf in bufs {
+    values.push(read_value(buf.as_ptr(), buf.len(), name)?);
+  }
+
+  Some(values)
+}
+
 #[no_mangle]
 pub extern "C" fn alloc(capacity: usize) -> *const u8 {
   let buffer = Vec::with_capacity(capacity);
@@ -1,4 +1,9 @@
+use snarkvm_circuit::AleoTestnetV0;
 use snarkvm_console::network::TestnetV0;
 
 pub type CurrentNetwork = TestnetV0;
 
+// Circuit environment of the current network, which evaluates program functions
+pub type CurrentAleo = AleoTestnetV0;
+
+pub const NETWORK_NAME: &str = "testnet";
@@ -0,0 +1,121 @@
+use alloc::{vec::Vec, string::ToString};
+
+use snarkvm_console::{
+  program::{Field, Identifier, Network, Plaintext, ProgramID, Value},
+  prelude::*,
+};
+
+use crate::{
+  log::log,
+  memory::{forget_buf_ptr_len, forget_bufs_ptr_len, read_value},
+  network::CurrentNetwork,
+};
+
+// Returns the address of a program, e.g. credits.aleo
+#[no_mangle]
+pub extern "C" fn program_address(program_id_str: *const u8, program_id_len: usize) -> u64 {
+  let program_id: ProgramID<CurrentNetwork> = match read_value(program_id_str, program_id_len, "program ID") {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let address = match program_id.to_address() {
+    Ok(val) => val,
+    Err(e) => {
+      let mut err_str = String::from("failed to compute program address: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return 0;
+    }
+  };
+
+  forget_buf_ptr_len(address.to_string().into_bytes())
+}
+
+// Computes the key ID of a mapping entry the same way as snarkVM's finalize store, which is used to look up
+// mapping values: BHP1024 hash of the program ID, the mapping name and the key bits separated with a false bit.
+fn to_key_id(program_id: &ProgramID<CurrentNetwork>, mapping_name: &Identifier<CurrentNetwork>, key: &Plaintext<CurrentNetwork>) -> Result<Field<CurrentNetwork>> {
+  let mut preimage = Vec::new();
+  program_id.write_bits_le(&mut preimage);
+  false.write_bits_le(&mut preimage);
+  mapping_name.write_bits_le(&mut preimage);
+  false.write_bits_le(&mut preimage);
+  key.write_bits_le(&mut preimage);
+
+  CurrentNetwork::hash_bhp1024(&preimage)
+}
+
+// Returns the key ID of a mapping entry as a field string. The key is a Leo plaintext value.
+#[no_mangle]
+pub extern "C" fn mapping_key_id(program_id_str: *const u8, program_id_len: usize, mapping_name_str: *const u8, mapping_name_len: usize, key_str: *const u8, key_len: usize) -> u64 {
+  let program_id: ProgramID<CurrentNetwork> = match read_value(program_id_str, program_id_len, "program ID") {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let mapping_name: Identifier<CurrentNetwork> = match read_value(mapping_name_str, mapping_name_len, "mapping name") {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let key: Plaintext<CurrentNetwork> = match read_plaintext(key_str, key_len) {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let key_id = match to_key_id(&program_id, &mapping_name, &key) {
+    Ok(val) => val,
+    Err(e) => {
+      let mut err_str = String::from("failed to compute mapping key ID: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return 0;
+    }
+  };
+
+  forget_buf_ptr_len(key_id.to_string().into_bytes())
+}
+
+// Restores a plaintext value from a string pointer, parsing it as a Leo value the same way as hash_message
+fn read_plaintext(plaintext_str: *const u8, plaintext_len: usize) -> Option<Plaintext<CurrentNetwork>> {
+  let value: Value<CurrentNetwork> = read_value(plaintext_str, plaintext_len, "plaintext")?;
+
+  match value {
+    Value::Plaintext(plaintext) => Some(plaintext),
+    _ => {
+      log("value is not a plaintext");
+      None
+    }
+  }
+}
+
+// Encodes a Leo plaintext value to field elements the same way snarkVM does before hashing or signing it.
+// Returns the field strings packed with their lengths.
+#[no_mangle]
+pub extern "C" fn plaintext_to_fields(plaintext_str: *const u8, plaintext_len: usize) -> u64 {
+  let plaintext = match read_plaintext(plaintext_str, plaintext_len) {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let fields = match plaintext.to_fields() {
+    Ok(val) => val,
+    Err(e) => {
+      let mut err_str = String::from("failed to convert plaintext to fields: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return 0;
+    }
+  };
+
+  let field_strings: Vec<String> = fields.iter().map(|field| field.to_string()).collect();
+  let bufs: Vec<&[u8]> = field_strings.iter().map(|field| field.as_bytes()).collect();
+
+  forget_bufs_ptr_len(&bufs)
+}
@@ -0,0 +1,282 @@
+use core::{str, slice};
+use alloc::string::ToString;
+
+use snarkvm_console::{
+  account::{GraphKey, PrivateKey, ViewKey},
+  prelude::FromStr,
+  program::{Ciphertext, Field, Identifier, Network, Plaintext, ProgramID, Record, Scalar},
+};
+
+use crate::{
+  log::log,
+  memory::{forget_buf_ptr_len, log_parse_error, read_str, read_value},
+  network::CurrentNetwork,
+};
+
+// Results of is_owner
+const RECORD_NOT_OWNER: u32 = 0;
+const RECORD_OWNER: u32 = 1;
+const RECORD_CHECK_FAILED: u32 = 2;
+
+// Restores a view key from a string pointer
+pub fn read_view_key(view_key_str: *const u8, view_key_len: usize) -> Option<ViewKey<CurrentNetwork>> {
+  // Convert a pointer to view key into a string
+  let view_key = unsafe {
+    match str::from_utf8(slice::from_raw_parts(view_key_str, view_key_len)) {
+      Ok(val) => val,
+      Err(e) => {
+        let mut err_str = String::from("failed to rebuild view key string from pointer: ");
+        err_str.push_str(e.to_string().as_str());
+
+        log(err_str);
+
+        return None;
+      }
+    }
+  };
+
+  // Convert the view key string into a ViewKey
+  match ViewKey::<CurrentNetwork>::from_str(view_key) {
+    Ok(vk) => Some(vk),
+    Err(e) => {
+      let mut err_str = String::from("failed to parse view key from string: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      None
+    }
+  }
+}
+
+// Restores a record ciphertext from a string pointer
+pub fn read_record_ciphertext(record_str: *const u8, record_len: usize) -> Option<Record<CurrentNetwork, Ciphertext<CurrentNetwork>>> {
+  // Convert a pointer to record into a string
+  let record = unsafe {
+    match str::from_utf8(slice::from_raw_parts(record_str, record_len)) {
+      Ok(val) => val,
+      Err(e) => {
+        let mut err_str = String::from("failed to rebuild record string from pointer: ");
+        err_str.push_str(e.to_string().as_str());
+
+        log(err_str);
+
+        return None;
+      }
+    }
+  };
+
+  // Convert the record string into a Record
+  match Record::<CurrentNetwork, Ciphertext<CurrentNetwork>>::from_str(record) {
+    Ok(val) => Some(val),
+    Err(e) => {
+      let mut err_str = String::from("failed to parse record ciphertext from string: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      None
+    }
+  }
+}
+
+// Decrypts a record ciphertext with a view key, returns the plaintext record string
+#[no_mangle]
+pub extern "C" fn decrypt_record(view_key_str: *const u8, view_key_len: usize, record_str: *const u8, record_len: usize) -> u64 {
+  let view_key = match read_view_key(view_key_str, view_key_len) {
+    Some(vk) => vk,
+    None => return 0,
+  };
+
+  let record = match read_record_ciphertext(record_str, record_len) {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let plaintext: Record<CurrentNetwork, Plaintext<CurrentNetwork>> = match record.decrypt(&view_key) {
+    Ok(val) => val,
+    Err(e) => {
+      let mut err_str = String::from("failed to decrypt record: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return 0;
+    }
+  };
+
+  forget_buf_ptr_len(plaintext.to_string().into_bytes())
+}
+
+// Checks if the view key owns the record ciphertext without decrypting the whole record
+#[no_mangle]
+pub extern "C" fn is_owner(view_key_str: *const u8, view_key_len: usize, record_str: *const u8, record_len: usize) -> u32 {
+  let view_key = match read_view_key(view_key_str, view_key_len) {
+    Some(vk) => vk,
+    None => return RECORD_CHECK_FAILED,
+  };
+
+  let record = match read_record_ciphertext(record_str, record_len) {
+    Some(val) => val,
+    None => return RECORD_CHECK_FAILED,
+  };
+
+  if record.is_owner(&view_key) {
+    RECORD_OWNER
+  } else {
+    RECORD_NOT_OWNER
+  }
+}
+
+// Encrypts a plaintext record to its owner using the randomizer scalar. The record nonce is replaced with the nonce
+// derived from the randomizer. Returns the record ciphertext string.
+#[no_mangle]
+pub extern "C" fn encrypt_record(record_str: *const u8, record_len: usize, randomizer_str: *const u8, randomizer_len: usize) -> u64 {
+  let record: Record<CurrentNetwork, Plaintext<CurrentNetwork>> = match read_value(record_str, record_len, "record") {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let randomizer: Scalar<CurrentNetwork> = match read_value(randomizer_str, randomizer_len, "randomizer") {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  // the nonce must match the randomizer for the owner to be able to decrypt the record
+  let nonce = CurrentNetwork::g_scalar_multiply(&randomizer);
+  let record = match Record::<CurrentNetwork, Plaintext<CurrentNetwork>>::from_plaintext(record.owner().clone(), record.data().clone(), nonce) {
+    Ok(val) => val,
+    Err(e) => {
+      let mut err_str = String::from("failed to rebuild record with nonce: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return 0;
+    }
+  };
+
+  let ciphertext = match record.encrypt(randomizer) {
+    Ok(val) => val,
+    Err(e) => {
+      let mut err_str = String::from("failed to encrypt record: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return 0;
+    }
+  };
+
+  forget_buf_ptr_len(ciphertext.to_string().into_bytes())
+}
+
+// Computes the commitment of a plaintext record of the program, returns it as a field string
+#[no_mangle]
+pub extern "C" fn record_commitment(record_str: *const u8, record_len: usize, program_id_str: *const u8, program_id_len: usize, record_name_str: *const u8, record_name_len: usize) -> u64 {
+  let record: Record<CurrentNetwork, Plaintext<CurrentNetwork>> = match read_value(record_str, record_len, "record") {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let program_id: ProgramID<CurrentNetwork> = match read_value(program_id_str, program_id_len, "program ID") {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let record_name: Identifier<CurrentNetwork> = match read_value(record_name_str, record_name_len, "record name") {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let commitment = match record.to_commitment(&program_id, &record_name) {
+    Ok(val) => val,
+    Err(e) => {
+      let mut err_str = String::from("failed to compute record commitment: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return 0;
+    }
+  };
+
+  forget_buf_ptr_len(commitment.to_string().into_bytes())
+}
+
+// Computes the serial number of a record from the owner's private key and the record commitment
+#[no_mangle]
+pub extern "C" fn record_serial_number(private_key_str: *const u8, private_key_len: usize, commitment_str: *const u8, commitment_len: usize) -> u64 {
+  let private_key: PrivateKey<CurrentNetwork> = match read_value(private_key_str, private_key_len, "private key") {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let commitment: Field<CurrentNetwork> = match read_value(commitment_str, commitment_len, "commitment") {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let serial_number = match Record::<CurrentNetwork, Plaintext<CurrentNetwork>>::serial_number(private_key, commitment) {
+    Ok(val) => val,
+    Err(e) => {
+      let mut err_str = String::from("failed to compute record serial number: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return 0;
+    }
+  };
+
+  forget_buf_ptr_len(serial_number.to_string().into_bytes())
+}
+
+// Restores a graph key from a private key, view key or graph key string
+fn read_graph_key(key_str: *const u8, key_len: usize) -> Option<GraphKey<CurrentNetwork>> {
+  let key = read_str(key_str, key_len, "key")?;
+
+  let graph_key = if let Ok(private_key) = PrivateKey::<CurrentNetwork>::from_str(key) {
+    GraphKey::try_from(private_key)
+  } else if let Ok(view_key) = ViewKey::<CurrentNetwork>::from_str(key) {
+    GraphKey::try_from(view_key)
+  } else {
+    GraphKey::from_str(key)
+  };
+
+  match graph_key {
+    Ok(val) => Some(val),
+    Err(e) => {
+      log_parse_error("graph key", e);
+      None
+    }
+  }
+}
+
+// Computes the tag of a record from the owner's private key, view key or graph key and the record commitment
+#[no_mangle]
+pub extern "C" fn record_tag(key_str: *const u8, key_len: usize, commitment_str: *const u8, commitment_len: usize) -> u64 {
+  let graph_key = match read_graph_key(key_str, key_len) {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let commitment: Field<CurrentNetwork> = match read_value(commitment_str, commitment_len, "commitment") {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let tag = match Record::<CurrentNetwork, Plaintext<CurrentNetwork>>::tag(graph_key, commitment) {
+    Ok(val) => val,
+    Err(e) => {
+      let mut err_str = String::from("failed to compute record tag: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return 0;
+    }
+  };
+
+  forget_buf_ptr_len(tag.to_string().into_bytes())
+}
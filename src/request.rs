@@ -0,0 +1,70 @@
+use alloc::{vec::Vec, string::ToString};
+
+use snarkvm_console::{
+  account::PrivateKey,
+  program::{Identifier, ProgramID, Request, Value, ValueType},
+};
+use rand::{rngs::StdRng, SeedableRng};
+
+use crate::{
+  log::log,
+  memory::{forget_buf_ptr_len, read_packed_values, read_value},
+  network::CurrentNetwork,
+};
+
+// Signs a request to execute a program function with the inputs, which are Leo values, and their types, e.g.
+// `u64.public` or `credits.record`. Returns the signed request serialized as JSON, including the transition
+// view key, the transition commitment and the input IDs.
+#[no_mangle]
+pub extern "C" fn sign_request(
+  private_key_str: *const u8, private_key_len: usize,
+  program_id_str: *const u8, program_id_len: usize,
+  function_name_str: *const u8, function_name_len: usize,
+  inputs_ptr: *const u8, inputs_len: usize,
+  input_types_ptr: *const u8, input_types_len: usize,
+) -> u64 {
+  let private_key: PrivateKey<CurrentNetwork> = match read_value(private_key_str, private_key_len, "private key") {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let program_id: ProgramID<CurrentNetwork> = match read_value(program_id_str, program_id_len, "program ID") {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let function_name: Identifier<CurrentNetwork> = match read_value(function_name_str, function_name_len, "function name") {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let inputs: Vec<Value<CurrentNetwork>> = match read_packed_values(inputs_ptr, inputs_len, "input") {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let input_types: Vec<ValueType<CurrentNetwork>> = match read_packed_values(input_types_ptr, input_types_len, "input type") {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  if inputs.len() != input_types.len() {
+    log("number of inputs doesn't match number of input types");
+    return 0;
+  }
+
+  // the request is signed as a root transition, so there's no parent transition view key
+  let request = match Request::sign(&private_key, program_id, function_name, inputs.into_iter(), &input_types, None, true, &mut StdRng::from_entropy()) {
+    Ok(val) => val,
+    Err(e) => {
+      let mut err_str = String::from("failed to sign request: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return 0;
+    }
+  };
+
+  forget_buf_ptr_len(request.to_string().into_bytes())
+}
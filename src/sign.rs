@@ -2,8 +2,8 @@ use core::{str, slice, ptr};
 use alloc::string::ToString;
 
 use snarkvm_console::{
-  account::{Address, PrivateKey}, prelude::{FromBytes, FromStr, ToFields},
-  program::{Literal, Plaintext, U128},
+  account::{Address, ComputeKey, PrivateKey, Signature}, prelude::{FromBytes, FromStr, Result, ToFields},
+  program::{Field, Literal, Network, Plaintext, Scalar, U128},
 };
 use rand::{rngs::StdRng, SeedableRng};
 
@@ -13,8 +13,8 @@ use crate::{
   network::CurrentNetwork,
 };
 
-#[no_mangle]
-pub extern "C" fn sign(private_key_str: *const u8, private_key_len: usize, hash_field_str: *const u8, hash_field_len: usize) -> *const u8 {
+// Restores a private key from a string pointer
+pub fn read_private_key(private_key_str: *const u8, private_key_len: usize) -> Option<PrivateKey<CurrentNetwork>> {
   // Convert a pointer to private key into a string
   let private_key = unsafe {
     match str::from_utf8(slice::from_raw_parts(private_key_str, private_key_len)) {
@@ -25,77 +25,256 @@ pub extern "C" fn sign(private_key_str: *const u8, private_key_len: usize, hash_
 
         log(err_str);
 
-        return ptr::null()
+        return None;
       }
     }
   };
 
-  // Convert private key string into a PrivateKey or return nullptr
-  let priv_key: PrivateKey<CurrentNetwork> = match PrivateKey::from_str(private_key) {
-    Ok(pk) => pk,
+  // Convert private key string into a PrivateKey
+  match PrivateKey::from_str(private_key) {
+    Ok(pk) => Some(pk),
     Err(e) => {
       let mut err_str = String::from("failed to parse private key from string: ");
       err_str.push_str(e.to_string().as_str());
 
       log(err_str);
 
-      return ptr::null();
+      None
     }
-  };
+  }
+}
 
-  // get the public key of the private key
-  let addr = match Address::try_from(priv_key) {
-    Ok(val) => val,
+// Derives the address of a private key for signature self verification
+pub fn private_key_address(priv_key: &PrivateKey<CurrentNetwork>) -> Option<Address<CurrentNetwork>> {
+  match Address::try_from(priv_key) {
+    Ok(val) => Some(val),
     Err(e) => {
       let mut err_str = String::from("failed to convert a private key to address: ");
       err_str.push_str(e.to_string().as_str());
 
       log(err_str);
 
-      return ptr::null();
+      None
+    }
+  }
+}
+
+// Domain separator of the deterministic nonce derivation
+const NONCE_DOMAIN: &str = "AleoUtilsSchnorrNonce0";
+
+// Derives a nonce from the private key and the message fields, similar to RFC 6979. The nonce is a Poseidon hash
+// of the domain separator, the private key seed and the message, so the same key and message always produce
+// the same nonce, while the nonce stays unpredictable without the private key. The derivation only uses snarkVM
+// hashes, so the signatures can be reproduced outside of the module.
+fn deterministic_nonce(priv_key: &PrivateKey<CurrentNetwork>, message: &[Field<CurrentNetwork>]) -> Result<Scalar<CurrentNetwork>> {
+  let mut input = Vec::with_capacity(2 + message.len());
+  input.push(Field::<CurrentNetwork>::new_domain_separator(NONCE_DOMAIN));
+  input.push(priv_key.seed());
+  input.extend_from_slice(message);
+
+  CurrentNetwork::hash_to_scalar_psd8(&input)
+}
+
+// Signs the message fields with the nonce, the same way as Signature::sign does with a random nonce
+fn sign_with_nonce(priv_key: &PrivateKey<CurrentNetwork>, message: &[Field<CurrentNetwork>], nonce: Scalar<CurrentNetwork>) -> Result<Signature<CurrentNetwork>> {
+  let compute_key = ComputeKey::try_from(priv_key)?;
+  let address = compute_key.to_address();
+
+  // challenge = HashToScalar(G^nonce, pk_sig, pr_sig, address, message)
+  let g_r = CurrentNetwork::g_scalar_multiply(&nonce);
+  let mut preimage = Vec::with_capacity(4 + message.len());
+  preimage.extend([g_r, compute_key.pk_sig(), compute_key.pr_sig(), *address].map(|point| point.to_x_coordinate()));
+  preimage.extend_from_slice(message);
+  let challenge = CurrentNetwork::hash_to_scalar_psd8(&preimage)?;
+
+  let response = nonce - (challenge * priv_key.sk_sig());
+
+  Ok(Signature::from((challenge, response, compute_key)))
+}
+
+// Signs a U128 number as a Leo plaintext literal. If the address of the private key is provided, the signature
+// is self verified with it. If deterministic is set, the nonce is derived from the private key and the number
+// instead of the entropy source.
+pub fn sign_u128(priv_key: &PrivateKey<CurrentNetwork>, addr: Option<&Address<CurrentNetwork>>, number: U128<CurrentNetwork>, deterministic: bool) -> Option<Signature<CurrentNetwork>> {
+  // turn the number into a plaintext literal, then get fields of that literal
+  let fields_for_signing = match Plaintext::<CurrentNetwork>::Literal(Literal::U128(number), Default::default()).to_fields() {
+    Ok(val) => val,
+    Err(e) => {
+      let mut err_str = String::from("failed to convert u128 plaintext value to fields: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return None;
+    },
+  };
+
+  // Sign or return None
+  let result = if deterministic {
+    deterministic_nonce(priv_key, &fields_for_signing)
+      .and_then(|nonce| sign_with_nonce(priv_key, &fields_for_signing, nonce))
+  } else {
+    priv_key.sign(&fields_for_signing, &mut StdRng::from_entropy())
+  };
+
+  let signature = match result {
+    Ok(sig) => sig,
+    Err(e) => {
+      let mut err_str = String::from("failed to sign fields with private key: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return None;
     }
   };
 
+  // self verify
+  if let Some(addr) = addr {
+    if !signature.verify(addr, &fields_for_signing) {
+      log("signature self check failed");
+      return None;
+    }
+  }
+
+  Some(signature)
+}
+
+// Sign options flags
+pub const SIGN_SKIP_SELF_CHECK: u32 = 1;
+pub const SIGN_DETERMINISTIC_NONCE: u32 = 2;
+
+#[no_mangle]
+pub extern "C" fn sign(private_key_str: *const u8, private_key_len: usize, hash_field_str: *const u8, hash_field_len: usize) -> *const u8 {
+  sign_with_options(private_key_str, private_key_len, hash_field_str, hash_field_len, 0)
+}
+
+#[no_mangle]
+pub extern "C" fn sign_with_options(private_key_str: *const u8, private_key_len: usize, hash_field_str: *const u8, hash_field_len: usize, flags: u32) -> *const u8 {
+  let priv_key = match read_private_key(private_key_str, private_key_len) {
+    Some(pk) => pk,
+    None => return ptr::null(),
+  };
+
+  // get the public key of the private key for self verification
+  let addr = if flags & SIGN_SKIP_SELF_CHECK == 0 {
+    match private_key_address(&priv_key) {
+      Some(val) => Some(val),
+      None => return ptr::null(),
+    }
+  } else {
+    None
+  };
+
   // restore the data for signing slice from the pointer
   let hash_field_bytes = unsafe {
     slice::from_raw_parts(hash_field_str, hash_field_len)
   };
 
   // when we're dealing with bytes, we only accept U128 number as LE bytes (should come from the hash)
-  // first we create a u128 value, then turn it into a plaintext literal, then get fields of that literal
-  let fields_for_signing = match U128::<CurrentNetwork>::from_bytes_le(hash_field_bytes)
-    .and_then(|integer| Ok(Plaintext::Literal(Literal::U128(integer), Default::default())))
-    .and_then(|plaintext| plaintext.to_fields()) {
+  let number = match U128::<CurrentNetwork>::from_bytes_le(hash_field_bytes) {
+    Ok(val) => val,
+    Err(e) => {
+      let mut err_str = String::from("failed to parse u128 plaintext value from bytes: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return ptr::null();
+    },
+  };
+
+  // Sign, convert the signature into a string, or return nullptr
+  let signature = match sign_u128(&priv_key, addr.as_ref(), number, flags & SIGN_DETERMINISTIC_NONCE != 0) {
+    Some(sig) => sig,
+    None => return ptr::null(),
+  };
+
+  let output_bytes = signature.to_string().into_bytes();
+  forget_buf_ptr(output_bytes)
+}
+
+#[no_mangle]
+pub extern "C" fn verify(address_str: *const u8, address_len: usize, hash_field_str: *const u8, hash_field_len: usize, signature_str: *const u8, signature_len: usize) -> u32 {
+  // Convert a pointer to address into a string
+  let address = unsafe {
+    match str::from_utf8(slice::from_raw_parts(address_str, address_len)) {
       Ok(val) => val,
       Err(e) => {
-        let mut err_str = String::from("failed to parse u128 plaintext value from bytes: ");
+        let mut err_str = String::from("failed to rebuild address string from pointer: ");
         err_str.push_str(e.to_string().as_str());
 
         log(err_str);
 
-        return ptr::null();
-    },
+        return 0;
+      }
+    }
   };
 
-  // Sign, convert the signature into a string, or return nullptr
-  let signature = match priv_key.sign(&fields_for_signing, &mut StdRng::from_entropy()) {
-    Ok(sig) => sig,
+  // Convert address string into an Address or return 0
+  let addr: Address<CurrentNetwork> = match Address::from_str(address) {
+    Ok(val) => val,
     Err(e) => {
-      let mut err_str = String::from("failed to sign fields with private key: ");
+      let mut err_str = String::from("failed to parse address from string: ");
       err_str.push_str(e.to_string().as_str());
 
       log(err_str);
 
-      return ptr::null();
+      return 0;
     }
   };
 
-  // self verify
-  if !signature.verify(&addr, &fields_for_signing) {
-    log("signature self check failed");
-    return ptr::null();
+  // Convert a pointer to signature into a string
+  let signature = unsafe {
+    match str::from_utf8(slice::from_raw_parts(signature_str, signature_len)) {
+      Ok(val) => val,
+      Err(e) => {
+        let mut err_str = String::from("failed to rebuild signature string from pointer: ");
+        err_str.push_str(e.to_string().as_str());
+
+        log(err_str);
+
+        return 0;
+      }
+    }
+  };
+
+  // Convert signature string into a Signature or return 0
+  let sig: Signature<CurrentNetwork> = match Signature::from_str(signature) {
+    Ok(val) => val,
+    Err(e) => {
+      let mut err_str = String::from("failed to parse signature from string: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return 0;
+    }
+  };
+
+  // restore the signed data slice from the pointer
+  let hash_field_bytes = unsafe {
+    slice::from_raw_parts(hash_field_str, hash_field_len)
+  };
+
+  // the message is interpreted exactly the same way as in sign
+  let fields_for_verification = match U128::<CurrentNetwork>::from_bytes_le(hash_field_bytes)
+    .and_then(|integer| Ok(Plaintext::Literal(Literal::U128(integer), Default::default())))
+    .and_then(|plaintext| plaintext.to_fields()) {
+      Ok(val) => val,
+      Err(e) => {
+        let mut err_str = String::from("failed to parse u128 plaintext value from bytes: ");
+        err_str.push_str(e.to_string().as_str());
+
+        log(err_str);
+
+        return 0;
+    },
+  };
+
+  if !sig.verify(&addr, &fields_for_verification) {
+    return 0;
   }
 
-  let output_bytes = signature.to_string().into_bytes();
-  forget_buf_ptr(output_bytes)
+  1
 }
@@ -0,0 +1,433 @@
+use core::{str, slice};
+use alloc::{vec::Vec, string::ToString};
+
+use serde::de::DeserializeOwned;
+use serde_json::Value;
+use snarkvm_console::{
+  prelude::{FromBytes, FromStr, Result},
+  program::{compute_function_id, Field, Group, Identifier, Network, ProgramID, U16},
+};
+use snarkvm_ledger_block::{Deployment, Execution, Fee, Input, Output, ProgramOwner, Transaction, Transition};
+
+use crate::{
+  log::log,
+  memory::forget_bufs_ptr_len,
+  network::CurrentNetwork,
+};
+
+// Parses a transaction from its JSON or its little-endian bytes. The JSON is detected by its opening brace,
+// which can't start the binary encoding, as it starts with a version byte.
+fn parse_transaction_buf(buf: &[u8]) -> Result<Transaction<CurrentNetwork>, String> {
+  let is_json = buf.iter().find(|c| !c.is_ascii_whitespace()) == Some(&b'{');
+
+  let transaction = if is_json {
+    match str::from_utf8(buf) {
+      Ok(val) => Transaction::<CurrentNetwork>::from_str(val),
+      Err(e) => {
+        let mut err_str = String::from("failed to rebuild transaction string from pointer: ");
+        err_str.push_str(e.to_string().as_str());
+
+        return Err(err_str);
+      }
+    }
+  } else {
+    Transaction::<CurrentNetwork>::from_bytes_le(buf)
+  };
+
+  transaction.map_err(|e| {
+    let mut err_str = String::from("failed to parse transaction: ");
+    err_str.push_str(e.to_string().as_str());
+
+    err_str
+  })
+}
+
+// Restores a transaction from a pointer to its JSON or its little-endian bytes
+pub fn read_transaction(transaction_ptr: *const u8, transaction_len: usize) -> Option<Transaction<CurrentNetwork>> {
+  let buf = unsafe {
+    slice::from_raw_parts(transaction_ptr, transaction_len)
+  };
+
+  match parse_transaction_buf(buf) {
+    Ok(val) => Some(val),
+    Err(e) => {
+      log(e);
+      None
+    }
+  }
+}
+
+// Parses a transaction from JSON or bytes. Returns packed buffers: the transaction in the canonical JSON form,
+// the base fee and the priority fee in microcredits as decimal strings, which are empty if the transaction has no fee.
+#[no_mangle]
+pub extern "C" fn parse_transaction(transaction_ptr: *const u8, transaction_len: usize) -> u64 {
+  let transaction = match read_transaction(transaction_ptr, transaction_len) {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let (base_fee, priority_fee) = match transaction.fee_transition() {
+    Some(fee) => match (fee.base_amount(), fee.priority_amount()) {
+      (Ok(base), Ok(priority)) => (base.to_string(), priority.to_string()),
+      _ => {
+        log("failed to read transaction fee amounts");
+        return 0;
+      }
+    },
+    None => (String::new(), String::new()),
+  };
+
+  // integer literals are printed with their type, e.g. 100u64
+  let base_fee = base_fee.trim_end_matches("u64");
+  let priority_fee = priority_fee.trim_end_matches("u64");
+
+  let json = transaction.to_string();
+  let bufs: Vec<&[u8]> = Vec::from([json.as_bytes(), base_fee.as_bytes(), priority_fee.as_bytes()]);
+
+  forget_bufs_ptr_len(&bufs)
+}
+
+// Names of the checks of check_transaction, they must match the Go TransactionCheck constants
+const CHECK_FORMAT: &str = "format";
+const CHECK_TRANSACTION_ID: &str = "transaction_id";
+const CHECK_TRANSITION_ID: &str = "transition_id";
+const CHECK_INPUT_ID: &str = "input_id";
+const CHECK_OUTPUT_ID: &str = "output_id";
+const CHECK_FEE_ID: &str = "fee_id";
+const CHECK_OWNER_SIGNATURE: &str = "owner_signature";
+
+// A failed check: the check name, the claimed ID of the failed transition or an empty string, the reason, and
+// the recomputed value for ID mismatches or an empty string
+struct CheckFailure {
+  check: &'static str,
+  transition: String,
+  message: String,
+  expected: String,
+}
+
+impl CheckFailure {
+  fn new<M: ToString>(check: &'static str, transition: &str, message: M) -> Self {
+    Self {
+      check,
+      transition: transition.to_string(),
+      message: message.to_string(),
+      expected: String::new(),
+    }
+  }
+
+  fn mismatch<E: ToString>(check: &'static str, transition: &str, message: &str, expected: E) -> Self {
+    Self {
+      check,
+      transition: transition.to_string(),
+      message: message.to_string(),
+      expected: expected.to_string(),
+    }
+  }
+}
+
+// Returns a member of a JSON object
+fn json_member<'a>(value: &'a Value, key: &str) -> Result<&'a Value, String> {
+  match value.get(key) {
+    Some(val) if !val.is_null() => Ok(val),
+    _ => {
+      let mut err_str = String::from("missing ");
+      err_str.push_str(key);
+
+      Err(err_str)
+    }
+  }
+}
+
+// Deserializes a member of a JSON object
+fn json_parse<T: DeserializeOwned>(value: &Value, key: &str) -> Result<T, String> {
+  serde_json::from_value(json_member(value, key)?.clone()).map_err(|e| {
+    let mut err_str = String::from("invalid ");
+    err_str.push_str(key);
+    err_str.push_str(": ");
+    err_str.push_str(e.to_string().as_str());
+
+    err_str
+  })
+}
+
+// Deserializes an optional member of a JSON object
+fn json_parse_optional<T: DeserializeOwned>(value: &Value, key: &str) -> Result<Option<T>, String> {
+  match value.get(key) {
+    Some(val) if !val.is_null() => json_parse(value, key).map(Some),
+    _ => Ok(None),
+  }
+}
+
+// Reads a claimed ID, which is compared to the recomputed one as a string, so a malformed ID is a mismatch
+fn json_id(value: &Value) -> Result<String, String> {
+  match json_member(value, "id")?.as_str() {
+    Some(val) => Ok(val.to_string()),
+    None => Err(String::from("invalid id: expected a string")),
+  }
+}
+
+// A transition with its claimed ID, which is not validated by the parser, and the transition rebuilt from
+// the components, or None if it can't be rebuilt
+struct UncheckedTransition {
+  claimed_id: String,
+  transition: Option<Transition<CurrentNetwork>>,
+}
+
+// Parses the components of a transition JSON and recomputes the transition ID. The snarkVM deserializer rejects
+// transitions with mismatching IDs, so the components are parsed separately.
+fn parse_transition(value: &Value, failures: &mut Vec<CheckFailure>) -> Result<UncheckedTransition, String> {
+  let claimed_id = json_id(value)?;
+  let program_id: ProgramID<CurrentNetwork> = json_parse(value, "program")?;
+  let function_name: Identifier<CurrentNetwork> = json_parse(value, "function")?;
+  let inputs: Vec<Input<CurrentNetwork>> = json_parse(value, "inputs")?;
+  let outputs: Vec<Output<CurrentNetwork>> = json_parse(value, "outputs")?;
+  let tpk: Group<CurrentNetwork> = json_parse(value, "tpk")?;
+  let tcm: Field<CurrentNetwork> = json_parse(value, "tcm")?;
+  let scm: Field<CurrentNetwork> = json_parse(value, "scm")?;
+
+  let transition = match Transition::<CurrentNetwork>::new(program_id, function_name, inputs, outputs, tpk, tcm, scm) {
+    Ok(transition) => {
+      let id = transition.id().to_string();
+      if id != claimed_id {
+        failures.push(CheckFailure::mismatch(CHECK_TRANSITION_ID, &claimed_id, "transition ID mismatch", id));
+      }
+
+      check_transition_io(&transition, &claimed_id, failures);
+      Some(transition)
+    },
+    Err(e) => {
+      failures.push(CheckFailure::new(CHECK_TRANSITION_ID, &claimed_id, e));
+      None
+    },
+  };
+
+  Ok(UncheckedTransition { claimed_id, transition })
+}
+
+// Checks the IDs of the inputs and outputs, which commit to their values
+fn check_transition_io(transition: &Transition<CurrentNetwork>, claimed_id: &str, failures: &mut Vec<CheckFailure>) {
+  let function_id = match compute_function_id(&U16::new(CurrentNetwork::ID), transition.program_id(), transition.function_name()) {
+    Ok(val) => val,
+    Err(e) => {
+      failures.push(CheckFailure::new(CHECK_TRANSITION_ID, claimed_id, e));
+      return;
+    }
+  };
+
+  let num_inputs = transition.inputs().len();
+
+  for (index, input) in transition.inputs().iter().enumerate() {
+    if !input.verify(function_id, transition.tcm(), index) {
+      let mut message = String::from("input ID mismatch at index ");
+      message.push_str(index.to_string().as_str());
+
+      failures.push(CheckFailure::new(CHECK_INPUT_ID, claimed_id, message));
+    }
+  }
+
+  for (index, output) in transition.outputs().iter().enumerate() {
+    if !output.verify(function_id, transition.tcm(), num_inputs + index) {
+      let mut message = String::from("output ID mismatch at index ");
+      message.push_str(index.to_string().as_str());
+
+      failures.push(CheckFailure::new(CHECK_OUTPUT_ID, claimed_id, message));
+    }
+  }
+}
+
+// Parses a fee JSON with its transition. Returns None if the fee transition can't be rebuilt.
+fn parse_fee(value: &Value, failures: &mut Vec<CheckFailure>) -> Result<(String, Option<Fee<CurrentNetwork>>), String> {
+  let transition = parse_transition(json_member(value, "transition")?, failures)?;
+  let global_state_root = json_parse(value, "global_state_root")?;
+  let proof = json_parse_optional(value, "proof")?;
+
+  let fee = match transition.transition {
+    Some(fee_transition) => match Fee::from(fee_transition, global_state_root, proof) {
+      Ok(fee) => Some(fee),
+      Err(e) => {
+        failures.push(CheckFailure::new(CHECK_FEE_ID, &transition.claimed_id, e));
+        None
+      },
+    },
+    None => None,
+  };
+
+  Ok((transition.claimed_id, fee))
+}
+
+// Checks that the fee is paid for the execution or deployment with the ID
+fn check_fee(fee: &Fee<CurrentNetwork>, claimed_id: &str, id: Field<CurrentNetwork>, failures: &mut Vec<CheckFailure>) {
+  match fee.deployment_or_execution_id() {
+    Ok(fee_id) if fee_id == id => {},
+    Ok(_) => failures.push(CheckFailure::mismatch(CHECK_FEE_ID, claimed_id, "fee is paid for another transaction", id)),
+    Err(e) => failures.push(CheckFailure::new(CHECK_FEE_ID, claimed_id, e)),
+  }
+}
+
+// Compares the claimed transaction ID with the ID of the transaction rebuilt from its components
+fn check_transaction_id(claimed_id: &str, recomputed: Result<Transaction<CurrentNetwork>>, failures: &mut Vec<CheckFailure>) {
+  match recomputed {
+    Ok(recomputed) => {
+      let id = recomputed.id().to_string();
+      if id != claimed_id {
+        failures.push(CheckFailure::mismatch(CHECK_TRANSACTION_ID, "", "transaction ID mismatch", id));
+      }
+    },
+    Err(e) => failures.push(CheckFailure::new(CHECK_TRANSACTION_ID, "", e)),
+  }
+}
+
+// Parses the optional fee of an execution or the required fee of a deployment or a fee transaction
+fn parse_transaction_fee(value: &Value, failures: &mut Vec<CheckFailure>) -> Result<Option<(String, Option<Fee<CurrentNetwork>>)>, String> {
+  match value.get("fee") {
+    Some(fee) if !fee.is_null() => parse_fee(fee, failures).map(Some),
+    _ => Ok(None),
+  }
+}
+
+// Runs the offline checks of a transaction JSON. The components are parsed without ID validation, and every
+// ID is recomputed. Returns an error if a component can't be parsed, and the claimed transaction ID otherwise.
+fn check(value: &Value, failures: &mut Vec<CheckFailure>) -> Result<String, String> {
+  let claimed_id = json_id(value)?;
+  let fee = parse_transaction_fee(value, failures)?;
+
+  match json_member(value, "type")?.as_str() {
+    Some("execute") => {
+      let execution = json_member(value, "execution")?;
+      let transition_values = match json_member(execution, "transitions")?.as_array() {
+        Some(val) => val,
+        None => return Err(String::from("invalid transitions: expected an array")),
+      };
+
+      let mut transitions = Vec::with_capacity(transition_values.len());
+      for transition in transition_values {
+        transitions.push(parse_transition(transition, failures)?.transition);
+      }
+      let global_state_root = json_parse(execution, "global_state_root")?;
+      let proof = json_parse_optional(execution, "proof")?;
+
+      // the transaction ID can't be recomputed if a transition or the fee can't be rebuilt
+      let transitions: Option<Vec<Transition<CurrentNetwork>>> = transitions.into_iter().collect();
+      let fee = match fee {
+        Some((fee_id, Some(fee))) => Some(Some((fee_id, fee))),
+        Some((_, None)) => None,
+        None => Some(None),
+      };
+      let (Some(transitions), Some(fee)) = (transitions, fee) else {
+        return Ok(claimed_id);
+      };
+
+      let execution = match Execution::from(transitions.into_iter(), global_state_root, proof) {
+        Ok(val) => val,
+        Err(e) => {
+          failures.push(CheckFailure::new(CHECK_TRANSACTION_ID, "", e));
+          return Ok(claimed_id);
+        }
+      };
+
+      if let Some((fee_id, fee)) = &fee {
+        match execution.to_execution_id() {
+          Ok(id) => check_fee(fee, fee_id, id, failures),
+          Err(e) => failures.push(CheckFailure::new(CHECK_FEE_ID, fee_id, e)),
+        }
+      }
+
+      check_transaction_id(&claimed_id, Transaction::from_execution(execution, fee.map(|(_, fee)| fee)), failures);
+    },
+    Some("deploy") => {
+      let owner: ProgramOwner<CurrentNetwork> = json_parse(value, "owner")?;
+      let deployment: Deployment<CurrentNetwork> = json_parse(value, "deployment")?;
+      let Some((fee_id, fee)) = fee else {
+        return Err(String::from("missing fee"));
+      };
+
+      let id = match deployment.to_deployment_id() {
+        Ok(val) => val,
+        Err(e) => {
+          failures.push(CheckFailure::new(CHECK_TRANSACTION_ID, "", e));
+          return Ok(claimed_id);
+        }
+      };
+
+      // the transaction constructor also checks the owner signature, which is reported as a separate check
+      if !owner.verify(id) {
+        failures.push(CheckFailure::new(CHECK_OWNER_SIGNATURE, "", "invalid program owner signature"));
+      }
+
+      if let Some(fee) = fee {
+        check_fee(&fee, &fee_id, id, failures);
+
+        if owner.verify(id) {
+          check_transaction_id(&claimed_id, Transaction::from_deployment(owner, deployment, fee), failures);
+        }
+      }
+    },
+    Some("fee") => {
+      let Some((_, fee)) = fee else {
+        return Err(String::from("missing fee"));
+      };
+
+      if let Some(fee) = fee {
+        check_transaction_id(&claimed_id, Transaction::from_fee(fee), failures);
+      }
+    },
+    _ => return Err(String::from("invalid type: expected execute, deploy or fee")),
+  }
+
+  Ok(claimed_id)
+}
+
+// Checks a transaction from JSON or bytes offline: recomputes the transaction ID, the transition IDs, the input and
+// output IDs, checks that the fee pays for the transaction, and checks the program owner signature of a deployment.
+// The JSON components are parsed without ID validation, so every mismatching ID is reported by its check. The binary
+// form is parsed with the snarkVM deserializer, which rejects mismatching IDs, so they are reported as a format failure.
+// Proofs aren't verified. Transactions don't include the requests of their transitions, so the request signatures,
+// which are only proven by the transition proofs, aren't checked either.
+//
+// Returns packed buffers: the claimed transaction ID, followed by the check name, the claimed transition ID, the reason
+// and the recomputed value of every failed check. The transaction ID is empty if the transaction can't be parsed.
+#[no_mangle]
+pub extern "C" fn check_transaction(transaction_ptr: *const u8, transaction_len: usize) -> u64 {
+  let buf = unsafe {
+    slice::from_raw_parts(transaction_ptr, transaction_len)
+  };
+
+  let is_json = buf.iter().find(|c| !c.is_ascii_whitespace()) == Some(&b'{');
+
+  let mut failures = Vec::new();
+  let result = if is_json {
+    serde_json::from_slice::<Value>(buf)
+      .map_err(|e| {
+        let mut err_str = String::from("failed to parse transaction: ");
+        err_str.push_str(e.to_string().as_str());
+
+        err_str
+      })
+      .and_then(|value| check(&value, &mut failures))
+  } else {
+    parse_transaction_buf(buf).and_then(|transaction| match serde_json::to_value(&transaction) {
+      Ok(value) => check(&value, &mut failures),
+      Err(e) => Err(e.to_string()),
+    })
+  };
+
+  let id = match result {
+    Ok(id) => id,
+    Err(e) => {
+      // the other checks are meaningless if the transaction can't be parsed
+      failures = Vec::from([CheckFailure::new(CHECK_FORMAT, "", e)]);
+      String::new()
+    },
+  };
+
+  let mut bufs: Vec<&[u8]> = Vec::with_capacity(1 + 4 * failures.len());
+  bufs.push(id.as_bytes());
+  for failure in failures.iter() {
+    bufs.push(failure.check.as_bytes());
+    bufs.push(failure.transition.as_bytes());
+    bufs.push(failure.message.as_bytes());
+    bufs.push(failure.expected.as_bytes());
+  }
+
+  forget_bufs_ptr_len(&bufs)
+}
@@ -0,0 +1,109 @@
+use alloc::string::ToString;
+
+use snarkvm_console::{
+  account::ViewKey,
+  program::{compute_function_id, Ciphertext, Field, Group, Identifier, Network, ProgramID, U16},
+};
+
+use crate::{
+  log::log,
+  memory::{forget_buf_ptr_len, read_value},
+  network::CurrentNetwork,
+};
+
+// Derives the transition view key from the view key of the transition signer and the transition public key,
+// returns it as a field string
+#[no_mangle]
+pub extern "C" fn transition_view_key(view_key_str: *const u8, view_key_len: usize, tpk_str: *const u8, tpk_len: usize) -> u64 {
+  let view_key: ViewKey<CurrentNetwork> = match read_value(view_key_str, view_key_len, "view key") {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let tpk: Group<CurrentNetwork> = match read_value(tpk_str, tpk_len, "transition public key") {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let tvk = (tpk * *view_key).to_x_coordinate();
+
+  forget_buf_ptr_len(tvk.to_string().into_bytes())
+}
+
+// Decrypts a private input or output ciphertext of a transition with the transition view key, returns the plaintext
+// string. Inputs are indexed from 0, outputs are indexed after the inputs.
+#[no_mangle]
+pub extern "C" fn decrypt_transition_ciphertext(
+  tvk_str: *const u8, tvk_len: usize,
+  program_id_str: *const u8, program_id_len: usize,
+  function_name_str: *const u8, function_name_len: usize,
+  ciphertext_str: *const u8, ciphertext_len: usize,
+  index: u32,
+) -> u64 {
+  let tvk: Field<CurrentNetwork> = match read_value(tvk_str, tvk_len, "transition view key") {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let program_id: ProgramID<CurrentNetwork> = match read_value(program_id_str, program_id_len, "program ID") {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let function_name: Identifier<CurrentNetwork> = match read_value(function_name_str, function_name_len, "function name") {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let ciphertext: Ciphertext<CurrentNetwork> = match read_value(ciphertext_str, ciphertext_len, "ciphertext") {
+    Some(val) => val,
+    None => return 0,
+  };
+
+  let index = match u16::try_from(index) {
+    Ok(val) => val,
+    Err(_) => {
+      log("transition ciphertext index is too large");
+      return 0;
+    }
+  };
+
+  // the ciphertext view key is derived the same way as in Request::sign
+  let function_id = match compute_function_id(&U16::new(CurrentNetwork::ID), &program_id, &function_name) {
+    Ok(val) => val,
+    Err(e) => {
+      let mut err_str = String::from("failed to compute function ID: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return 0;
+    }
+  };
+
+  let ciphertext_view_key = match CurrentNetwork::hash_psd4(&[function_id, tvk, Field::from_u16(index)]) {
+    Ok(val) => val,
+    Err(e) => {
+      let mut err_str = String::from("failed to compute ciphertext view key: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return 0;
+    }
+  };
+
+  let plaintext = match ciphertext.decrypt_symmetric(ciphertext_view_key) {
+    Ok(val) => val,
+    Err(e) => {
+      let mut err_str = String::from("failed to decrypt transition ciphertext: ");
+      err_str.push_str(e.to_string().as_str());
+
+      log(err_str);
+
+      return 0;
+    }
+  };
+
+  forget_buf_ptr_len(plaintext.to_string().into_bytes())
+}
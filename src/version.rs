@@ -0,0 +1,10 @@
+use alloc::string::String;
+
+use crate::memory::forget_buf_ptr_len;
+
+#[no_mangle]
+pub extern "C" fn version() -> u64 {
+  let version = String::from(env!("CARGO_PKG_VERSION"));
+
+  forget_buf_ptr_len(version.into_bytes())
+}
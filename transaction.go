@@ -0,0 +1,315 @@
+package aleo_utils
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+)
+
+// transaction types
+const (
+	TransactionTypeDeploy  = "deploy"
+	TransactionTypeExecute = "execute"
+	TransactionTypeFee     = "fee"
+)
+
+// Transaction is a parsed Aleo transaction.
+type Transaction struct {
+	// `at1...` transaction ID
+	ID string
+	// one of TransactionTypeDeploy, TransactionTypeExecute, TransactionTypeFee
+	Type string
+	// transitions of an execution, empty for other transaction types
+	Transitions []Transition
+	// address of the program owner for a deployment
+	Owner string
+	// source code of the deployed program for a deployment
+	Program string
+	// fee of the transaction, nil if the transaction has no fee
+	Fee *Fee
+}
+
+// Fee is a fee transition of a transaction, the amounts are in microcredits.
+type Fee struct {
+	Transition     Transition
+	BaseAmount     uint64
+	PriorityAmount uint64
+}
+
+// Transition is a single program function call in a transaction.
+type Transition struct {
+	// `au1...` transition ID
+	ID string `json:"id"`
+	// program ID, e.g. `credits.aleo`
+	Program string `json:"program"`
+	// function name, e.g. `transfer_public`
+	Function string         `json:"function"`
+	Inputs   []TransitionIO `json:"inputs"`
+	Outputs  []TransitionIO `json:"outputs"`
+	// transition public key, a group literal
+	TPK string `json:"tpk"`
+	// transition commitment, a field literal
+	TCM string `json:"tcm"`
+	// signer commitment, a field literal
+	SCM string `json:"scm"`
+}
+
+// TransitionIO is an input or an output of a transition.
+type TransitionIO struct {
+	// visibility of the value: `constant`, `public`, `private`, `record`, `external_record` or `future`
+	Type string `json:"type"`
+	// input or output ID, a field literal. For record inputs, it's the record serial number, for record outputs,
+	// it's the record commitment
+	ID string `json:"id"`
+	// Leo value for constant, public and future values, `ciphertext1...` for private values, `record1...` for
+	// record outputs. Empty if the value is not published
+	Value string `json:"value,omitempty"`
+	// record tag for record inputs
+	Tag string `json:"tag,omitempty"`
+	// record checksum for record outputs
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// rawTransaction is the JSON form of a transaction in snarkVM
+type rawTransaction struct {
+	Type      string `json:"type"`
+	ID        string `json:"id"`
+	Execution *struct {
+		Transitions []Transition `json:"transitions"`
+	} `json:"execution"`
+	Deployment *struct {
+		Program string `json:"program"`
+	} `json:"deployment"`
+	Owner *struct {
+		Address string `json:"address"`
+	} `json:"owner"`
+	Fee *struct {
+		Transition Transition `json:"transition"`
+	} `json:"fee"`
+}
+
+// ParseTransaction parses a transaction in the JSON form, as returned by the node REST API, or in the binary form,
+// using the snarkVM deserializer, which checks the transaction and transition IDs.
+func (s *aleoWrapperSession) ParseTransaction(transaction []byte) (tx *Transaction, err error) {
+	if s.mod == nil || s.mod.IsClosed() {
+		return nil, ErrNoModule
+	}
+
+	if s.parseTransaction == nil {
+		return nil, ErrNotSupported
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// find out exactly what the error was and set err
+			switch x := r.(type) {
+			case string:
+				err = errors.New(x)
+			case error:
+				err = x
+			default:
+				err = errors.New("unknown panic")
+			}
+			tx = nil
+		}
+	}()
+
+	if len(transaction) == 0 {
+		return nil, errors.New("empty transaction")
+	}
+
+	if err := s.resetArena(len(transaction)); err != nil {
+		log.Println("arena error:", err)
+		return nil, errors.New("failed to allocate memory for transaction")
+	}
+
+	// write transaction to wasm memory
+	transactionPtr, err := s.putBytes(transaction)
+	if err != nil {
+		return nil, errors.New("failed to write transaction to memory")
+	}
+
+	result, err := s.call(s.parseTransaction, transactionPtr, uint64(len(transaction)))
+	if err != nil {
+		log.Println("parse_transaction error:", err)
+		return nil, errors.New("failed to parse transaction")
+	}
+	if result == 0 {
+		return nil, errors.New("invalid transaction")
+	}
+
+	buf, ok := s.readResult(unpackResult(result))
+	if !ok {
+		return nil, errors.New("failed to parse transaction")
+	}
+
+	fields, ok := unpackFields(buf, 3)
+	if !ok {
+		return nil, errors.New("invalid transaction returned by the module")
+	}
+
+	return decodeTransaction(fields[0], fields[1], fields[2])
+}
+
+// decodeTransaction converts a transaction JSON and the fee amounts returned by the module to a Transaction
+func decodeTransaction(transactionJSON []byte, baseFee []byte, priorityFee []byte) (*Transaction, error) {
+	raw := new(rawTransaction)
+	if err := json.Unmarshal(transactionJSON, raw); err != nil {
+		return nil, errors.New("invalid transaction returned by the module")
+	}
+
+	tx := &Transaction{
+		ID:   raw.ID,
+		Type: raw.Type,
+	}
+
+	if raw.Execution != nil {
+		tx.Transitions = raw.Execution.Transitions
+	}
+	if raw.Deployment != nil {
+		tx.Program = raw.Deployment.Program
+	}
+	if raw.Owner != nil {
+		tx.Owner = raw.Owner.Address
+	}
+
+	if raw.Fee != nil {
+		base, err := strconv.ParseUint(string(baseFee), 10, 64)
+		if err != nil {
+			return nil, errors.New("invalid base fee returned by the module")
+		}
+		priority, err := strconv.ParseUint(string(priorityFee), 10, 64)
+		if err != nil {
+			return nil, errors.New("invalid priority fee returned by the module")
+		}
+
+		tx.Fee = &Fee{
+			Transition:     raw.Fee.Transition,
+			BaseAmount:     base,
+			PriorityAmount: priority,
+		}
+	}
+
+	return tx, nil
+}
+
+// names of the transaction checks of CheckTransactionConsistency
+const (
+	// the transaction can't be parsed. The binary form is parsed with the snarkVM deserializer, which rejects
+	// mismatching IDs, so they are reported as a format failure for binary transactions
+	TransactionCheckFormat = "format"
+	// the transaction ID doesn't match the transitions and the fee
+	TransactionCheckID = "transaction_id"
+	// the transition ID doesn't match the transition inputs and outputs
+	TransactionCheckTransitionID = "transition_id"
+	// an input ID doesn't match the input value
+	TransactionCheckInputID = "input_id"
+	// an output ID doesn't match the output value
+	TransactionCheckOutputID = "output_id"
+	// the fee pays for another execution or deployment
+	TransactionCheckFeeID = "fee_id"
+	// the program owner signature of a deployment is invalid
+	TransactionCheckOwnerSignature = "owner_signature"
+)
+
+// TransactionCheckFailure describes a failed check of CheckTransactionConsistency.
+type TransactionCheckFailure struct {
+	// one of TransactionCheck constants
+	Check string
+	// ID of the failed transition, empty if the check is not specific to a transition
+	Transition string
+	// reason of the failure
+	Message string
+	// recomputed transaction or transition ID for ID mismatches, or the ID of the execution or deployment for fees
+	// paid for another transaction, empty otherwise
+	Expected string
+}
+
+// CheckTransactionConsistency checks that a transaction in the JSON or the binary form is internally consistent:
+// recomputes the transaction ID, the transition IDs and the input and output IDs, checks that the fee pays for
+// the transaction, and verifies the program owner signature of a deployment. Returns the failed checks,
+// the transaction is consistent if there are none.
+//
+// The components of a JSON transaction are parsed without ID validation, so every tampered ID is reported by its own
+// check. Binary transactions with mismatching IDs fail the format check.
+//
+// A consistent transaction is not necessarily valid. The request signatures of the transitions are not checked,
+// as transactions don't include the requests and the signatures are only attested by the transition proofs, and
+// the proofs are not verified. Use a node to verify a transaction before trusting it.
+func (s *aleoWrapperSession) CheckTransactionConsistency(transaction []byte) (failures []TransactionCheckFailure, err error) {
+	if s.mod == nil || s.mod.IsClosed() {
+		return nil, ErrNoModule
+	}
+
+	if s.checkTransaction == nil {
+		return nil, ErrNotSupported
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// find out exactly what the error was and set err
+			switch x := r.(type) {
+			case string:
+				err = errors.New(x)
+			case error:
+				err = x
+			default:
+				err = errors.New("unknown panic")
+			}
+			failures = nil
+		}
+	}()
+
+	if len(transaction) == 0 {
+		return nil, errors.New("empty transaction")
+	}
+
+	if err := s.resetArena(len(transaction)); err != nil {
+		log.Println("arena error:", err)
+		return nil, errors.New("failed to allocate memory for transaction")
+	}
+
+	// write transaction to wasm memory
+	transactionPtr, err := s.putBytes(transaction)
+	if err != nil {
+		return nil, errors.New("failed to write transaction to memory")
+	}
+
+	result, err := s.call(s.checkTransaction, transactionPtr, uint64(len(transaction)))
+	if err != nil {
+		log.Println("check_transaction error:", err)
+		return nil, errors.New("failed to check transaction")
+	}
+	if result == 0 {
+		return nil, errors.New("internal error when checking transaction")
+	}
+
+	buf, ok := s.readResult(unpackResult(result))
+	if !ok {
+		return nil, errors.New("failed to check transaction")
+	}
+
+	return decodeCheckFailures(buf)
+}
+
+// decodeCheckFailures decodes the packed transaction ID followed by the check name, the transition ID,
+// the message and the expected value of every failed check
+func decodeCheckFailures(buf []byte) ([]TransactionCheckFailure, error) {
+	fields, ok := unpackAllFields(buf, 1)
+	if !ok || len(fields) == 0 || (len(fields)-1)%4 != 0 {
+		return nil, errors.New("invalid transaction check returned by the module")
+	}
+
+	failures := make([]TransactionCheckFailure, 0, (len(fields)-1)/4)
+	for i := 1; i < len(fields); i += 4 {
+		failures = append(failures, TransactionCheckFailure{
+			Check:      string(fields[i]),
+			Transition: string(fields[i+1]),
+			Message:    string(fields[i+2]),
+			Expected:   string(fields[i+3]),
+		})
+	}
+
+	return failures, nil
+}
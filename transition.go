@@ -0,0 +1,114 @@
+package aleo_utils
+
+import (
+	"errors"
+	"log"
+	"math"
+
+	"github.com/zkportal/aleo-utils-go/codec"
+	"github.com/zkportal/aleo-utils-go/literal"
+)
+
+// TransitionViewKey derives the transition view key from the view key of the transition signer and the transition
+// public key (tpk), which is a group literal. Returns the transition view key as a field literal.
+func (s *aleoWrapperSession) TransitionViewKey(viewKey string, tpk string) (tvk string, err error) {
+	if s.mod == nil || s.mod.IsClosed() {
+		return "", ErrNoModule
+	}
+
+	if s.transitionViewKey == nil {
+		return "", ErrNotSupported
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// find out exactly what the error was and set err
+			switch x := r.(type) {
+			case string:
+				err = errors.New(x)
+			case error:
+				err = x
+			default:
+				err = errors.New("unknown panic")
+			}
+			tvk = ""
+		}
+	}()
+
+	if err := codec.ValidateViewKey(viewKey); err != nil {
+		return "", err
+	}
+	if _, err := literal.ParseGroup(tpk); err != nil {
+		return "", err
+	}
+
+	tvk, err = s.callWithStrings(s.transitionViewKey, []string{viewKey, tpk})
+	if err != nil {
+		log.Println("transition_view_key error:", err)
+		return "", errors.New("failed to derive transition view key")
+	}
+
+	return tvk, nil
+}
+
+// DecryptTransitionInput decrypts a `ciphertext1...` private input of a transition of the program function
+// using the transition view key. The index is the position of the input in the transition. Returns the input
+// plaintext in the Leo format.
+func (s *aleoWrapperSession) DecryptTransitionInput(tvk string, programID string, functionName string, index int, ciphertext string) (plaintext string, err error) {
+	return s.decryptTransitionCiphertext(tvk, programID, functionName, index, ciphertext)
+}
+
+// DecryptTransitionOutput is like DecryptTransitionInput, but decrypts a private output. Outputs are encrypted
+// with indexes following the inputs, so the number of the transition inputs is required.
+func (s *aleoWrapperSession) DecryptTransitionOutput(tvk string, programID string, functionName string, numInputs int, index int, ciphertext string) (plaintext string, err error) {
+	if numInputs < 0 || index < 0 {
+		return "", errors.New("invalid transition output index")
+	}
+
+	return s.decryptTransitionCiphertext(tvk, programID, functionName, numInputs+index, ciphertext)
+}
+
+func (s *aleoWrapperSession) decryptTransitionCiphertext(tvk string, programID string, functionName string, index int, ciphertext string) (plaintext string, err error) {
+	if s.mod == nil || s.mod.IsClosed() {
+		return "", ErrNoModule
+	}
+
+	if s.decryptTransition == nil {
+		return "", ErrNotSupported
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// find out exactly what the error was and set err
+			switch x := r.(type) {
+			case string:
+				err = errors.New(x)
+			case error:
+				err = x
+			default:
+				err = errors.New("unknown panic")
+			}
+			plaintext = ""
+		}
+	}()
+
+	if index < 0 || index > math.MaxUint16 {
+		return "", errors.New("invalid transition ciphertext index")
+	}
+
+	if _, err := literal.ParseField(tvk); err != nil {
+		return "", err
+	}
+
+	if err := codec.ValidateCiphertext(ciphertext); err != nil {
+		return "", err
+	}
+
+	plaintext, err = s.callWithStrings(s.decryptTransition, []string{tvk, programID, functionName, ciphertext}, uint64(index))
+	if err != nil {
+		log.Println("decrypt_transition_ciphertext error:", err)
+		return "", errors.New("failed to decrypt transition ciphertext")
+	}
+
+	return plaintext, nil
+}
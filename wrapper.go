@@ -4,10 +4,16 @@ package aleo_utils
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	_ "embed"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"log"
+	"os"
+	"path/filepath"
+	"runtime/debug"
 
 	"github.com/tetratelabs/wazero"
 	"github.com/tetratelabs/wazero/api"
@@ -22,6 +28,7 @@ var ErrNoRuntime = errors.New("no runtime, create new wrapper")
 const (
 	PRIVATE_KEY_SIZE          = 59
 	ADDRESS_SIZE              = 63
+	VIEW_KEY_SIZE             = 53
 	SIGNATURE_SIZE            = 216
 	MESSAGE_FORMAT_BLOCK_SIZE = 16 * 32
 	MAX_FORMAT_MESSAGE_CHUNKS = 32
@@ -31,6 +38,10 @@ const (
 // NewWrapper, then create a new Session to use the signing functionality.
 type Wrapper interface {
 	NewSession() (Session, error)
+	// ModuleVersion returns the version of the WASM module, or an empty string if the module doesn't report it.
+	ModuleVersion() string
+	// Capabilities returns the features supported by the WASM module.
+	Capabilities() Capabilities
 	Close()
 }
 
@@ -45,15 +56,19 @@ type aleoWrapper struct {
 	Wrapper
 
 	runtime       wazero.Runtime
+	cache         wazero.CompilationCache
 	cmod          wazero.CompiledModule
 	moduleConfig  wazero.ModuleConfig
+	signOptions   SignOptions
+	version       string
+	capabilities  Capabilities
 	runtimeActive bool // a simple guard against using wrapper after it's runtime was destroyed
 }
 
 // NewWrapper creates Leo contract compatible Schnorr wrapper manager.
 // The second argument is a cleanup function, which destroys wrapper runtime.
 // aleoWrapper cannot be used after the cleanup function is called, and must be recreated using this function.
-func NewWrapper() (wrapper Wrapper, closeFn func(), err error) {
+func NewWrapper(opts ...WrapperOption) (wrapper Wrapper, closeFn func(), err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			// find out exactly what the error was and set err
@@ -70,36 +85,157 @@ func NewWrapper() (wrapper Wrapper, closeFn func(), err error) {
 		}
 	}()
 
+	options := new(wrapperOptions)
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	ctx := context.Background()
 
-	runtimeConfig := wazero.NewRuntimeConfigCompiler()
-	runtime := wazero.NewRuntimeWithConfig(ctx, runtimeConfig)
+	module, err := loadModule(options)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	// export some wasi system functions
-	wasi_snapshot_preview1.MustInstantiate(ctx, runtime)
+	runtimeConfig, err := newRuntimeConfig(options.runtimeMode)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	// export logging function to the guest
-	hostBuilder := runtime.NewHostModuleBuilder("env")
-	hostBuilder.NewFunctionBuilder().WithFunc(logString).Export("host_log_string").Instantiate(ctx)
+	var cache wazero.CompilationCache
+	var cachePath string
+	if options.compilationCacheDir != "" {
+		cachePath = compilationCachePath(options.compilationCacheDir, module)
+		cache, err = wazero.NewCompilationCacheWithDir(cachePath)
+		if err != nil {
+			log.Println("failed to open compilation cache, compiling without cache:", err)
+			cache = nil
+		}
+	}
 
-	moduleConfig := wazero.NewModuleConfig().WithRandSource(rand.Reader)
+	runtime, cmod, err := newRuntime(ctx, runtimeConfig, cache, module)
+	if err != nil && cache != nil {
+		// the cache may be corrupted, discard it and compile the module again
+		log.Println("failed to compile wrapper WASM module using compilation cache, recompiling:", err)
+		cache.Close(ctx)
+		os.RemoveAll(cachePath)
 
-	cmod, err := runtime.CompileModule(ctx, wasmBytes)
+		cache, err = wazero.NewCompilationCacheWithDir(cachePath)
+		if err != nil {
+			log.Println("failed to recreate compilation cache, compiling without cache:", err)
+			cache = nil
+		}
+
+		runtime, cmod, err = newRuntime(ctx, runtimeConfig, cache, module)
+	}
+	if err != nil {
+		if cache != nil {
+			cache.Close(ctx)
+		}
+		return nil, nil, err
+	}
+	log.Println("compiled wrapper WASM module, runtime mode:", options.runtimeMode)
+
+	var randSource io.Reader = rand.Reader
+	if options.randSource != nil {
+		randSource = &lockedReader{r: options.randSource}
+	}
+	moduleConfig := wazero.NewModuleConfig().WithRandSource(randSource)
+
+	closeRuntime := func() {
+		runtime.Close(ctx)
+		if cache != nil {
+			cache.Close(ctx)
+		}
+	}
+
+	if err := validateModule(cmod); err != nil {
+		closeRuntime()
+		return nil, nil, err
+	}
+
+	info, err := probeModule(ctx, runtime, cmod, moduleConfig)
 	if err != nil {
+		closeRuntime()
 		return nil, nil, err
 	}
-	log.Println("compiled wrapper WASM module")
 
 	wrapper = &aleoWrapper{
 		runtime:       runtime,
+		cache:         cache,
 		cmod:          cmod,
 		moduleConfig:  moduleConfig,
+		signOptions:   options.signOptions,
+		version:       info.version,
+		capabilities:  info.capabilities,
 		runtimeActive: true,
 	}
 
 	return wrapper, wrapper.Close, nil
 }
 
+// newRuntimeConfig returns a runtime config for the engine selected by the runtime mode.
+func newRuntimeConfig(mode RuntimeMode) (wazero.RuntimeConfig, error) {
+	switch mode {
+	case RuntimeModeAuto:
+		return wazero.NewRuntimeConfig(), nil
+	case RuntimeModeCompiler:
+		return wazero.NewRuntimeConfigCompiler(), nil
+	case RuntimeModeInterpreter:
+		return wazero.NewRuntimeConfigInterpreter(), nil
+	default:
+		return nil, fmt.Errorf("unknown runtime mode %d", mode)
+	}
+}
+
+// newRuntime creates a WASM runtime, exports host functions to it, and compiles the wrapper module.
+func newRuntime(ctx context.Context, runtimeConfig wazero.RuntimeConfig, cache wazero.CompilationCache, module []byte) (wazero.Runtime, wazero.CompiledModule, error) {
+	if cache != nil {
+		runtimeConfig = runtimeConfig.WithCompilationCache(cache)
+	}
+	runtime := wazero.NewRuntimeWithConfig(ctx, runtimeConfig)
+
+	// export some wasi system functions
+	wasi_snapshot_preview1.MustInstantiate(ctx, runtime)
+
+	// export logging function to the guest
+	hostBuilder := runtime.NewHostModuleBuilder("env")
+	hostBuilder.NewFunctionBuilder().WithFunc(logString).Export("host_log_string").Instantiate(ctx)
+
+	cmod, err := runtime.CompileModule(ctx, module)
+	if err != nil {
+		runtime.Close(ctx)
+		return nil, nil, err
+	}
+
+	return runtime, cmod, nil
+}
+
+// compilationCachePath returns a cache subdirectory for the wrapper module. Compiled code is only valid for the same
+// module and wazero version, so both are used as a cache key.
+func compilationCachePath(dir string, module []byte) string {
+	hash := sha256.Sum256(module)
+	return filepath.Join(dir, hex.EncodeToString(hash[:])+"-"+wazeroVersion())
+}
+
+func wazeroVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path == "github.com/tetratelabs/wazero" {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+
+	return "unknown"
+}
+
 // NewSession creates a new wrapper session, which can used to access signing logic. Sessions
 // are not goroutine-safe.
 func (s *aleoWrapper) NewSession() (Session, error) {
@@ -114,26 +250,67 @@ func (s *aleoWrapper) NewSession() (Session, error) {
 	}
 
 	session := &aleoWrapperSession{
-		mod:              mod,
-		ctx:              context.Background(),
-		newPrivateKey:    mod.ExportedFunction("new_private_key"),
-		getAddress:       mod.ExportedFunction("get_address"),
-		sign:             mod.ExportedFunction("sign"),
-		allocate:         mod.ExportedFunction("alloc"),
-		deallocate:       mod.ExportedFunction("dealloc"),
-		hashMessage:      mod.ExportedFunction("hash_message"),
-		hashMessageBytes: mod.ExportedFunction("hash_message_bytes"),
-		formatMessage:    mod.ExportedFunction("format_message"),
-		recoverMessage:   mod.ExportedFunction("formatted_message_to_bytes"),
+		mod:                mod,
+		ctx:                context.Background(),
+		newPrivateKey:      mod.ExportedFunction("new_private_key"),
+		getAddress:         mod.ExportedFunction("get_address"),
+		sign:               mod.ExportedFunction("sign"),
+		allocate:           mod.ExportedFunction("alloc"),
+		deallocate:         mod.ExportedFunction("dealloc"),
+		hashMessage:        mod.ExportedFunction("hash_message"),
+		hashMessageBytes:   mod.ExportedFunction("hash_message_bytes"),
+		formatMessage:      mod.ExportedFunction("format_message"),
+		recoverMessage:     mod.ExportedFunction("formatted_message_to_bytes"),
+		verify:             mod.ExportedFunction("verify"),
+		formatAndHash:      mod.ExportedFunction("format_and_hash"),
+		formatHashSign:     mod.ExportedFunction("format_hash_sign"),
+		hashMessageBatch:   mod.ExportedFunction("hash_message_batch"),
+		signBatch:          mod.ExportedFunction("sign_batch"),
+		loadKey:            mod.ExportedFunction("load_key"),
+		releaseKey:         mod.ExportedFunction("release_key"),
+		releaseAllKeys:     mod.ExportedFunction("release_all_keys"),
+		signWithKey:        mod.ExportedFunction("sign_with_key"),
+		signWithOptions:    mod.ExportedFunction("sign_with_options"),
+		decryptRecord:      mod.ExportedFunction("decrypt_record"),
+		isOwner:            mod.ExportedFunction("is_owner"),
+		encryptRecord:      mod.ExportedFunction("encrypt_record"),
+		recordCommitment:   mod.ExportedFunction("record_commitment"),
+		recordSerialNumber: mod.ExportedFunction("record_serial_number"),
+		recordTag:          mod.ExportedFunction("record_tag"),
+		transitionViewKey:  mod.ExportedFunction("transition_view_key"),
+		decryptTransition:  mod.ExportedFunction("decrypt_transition_ciphertext"),
+		programAddress:     mod.ExportedFunction("program_address"),
+		mappingKeyID:       mod.ExportedFunction("mapping_key_id"),
+		plaintextToFields:  mod.ExportedFunction("plaintext_to_fields"),
+		signRequest:        mod.ExportedFunction("sign_request"),
+		authorizeFee:       mod.ExportedFunction("authorize_fee_public"),
+		parseTransaction:   mod.ExportedFunction("parse_transaction"),
+		checkTransaction:   mod.ExportedFunction("check_transaction"),
+		signOptions:        s.signOptions,
+		deterministicNonce: s.capabilities.HasFeature(FeatureDeterministicNonce),
 	}
 
 	return session, nil
 }
 
+// ModuleVersion returns the version of the WASM module, or an empty string if the module doesn't report it.
+func (s *aleoWrapper) ModuleVersion() string {
+	return s.version
+}
+
+// Capabilities returns the features supported by the WASM module.
+func (s *aleoWrapper) Capabilities() Capabilities {
+	return s.capabilities
+}
+
 // Closes WASM runtime
 func (s *aleoWrapper) Close() {
 	if s.runtime != nil {
 		s.runtime.Close(context.Background())
 	}
+	if s.cache != nil {
+		s.cache.Close(context.Background())
+		s.cache = nil
+	}
 	s.runtimeActive = false
 }
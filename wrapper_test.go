@@ -1,11 +1,30 @@
 package aleo_utils
 
 import (
+	"bytes"
+	"crypto/sha256"
 	_ "embed"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"io/fs"
 	"log"
+	"math"
+	mathrand "math/rand"
+	"os"
+	"path/filepath"
 	"reflect"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
+
+	"github.com/zkportal/aleo-utils-go/codec"
+	"github.com/zkportal/aleo-utils-go/field"
+	"github.com/zkportal/aleo-utils-go/literal"
+	"github.com/zkportal/aleo-utils-go/poseidon"
 )
 
 func TestAleoWrapper_NewAleoWrapper(t *testing.T) {
@@ -27,6 +46,235 @@ func TestAleoWrapper_NewAleoWrapper(t *testing.T) {
 	}
 }
 
+func TestAleoWrapper_CompilationCache(t *testing.T) {
+	cacheDir := t.TempDir()
+
+	// cold start populates the cache, warm start uses it
+	for i := 0; i < 2; i++ {
+		wrapper, closeFn, err := NewWrapper(WithCompilationCache(cacheDir))
+		if err != nil {
+			t.Fatalf("NewWrapper error = %v\n", err)
+		}
+
+		s, err := wrapper.NewSession()
+		if err != nil {
+			t.Fatalf("NewSession error = %v\n", err)
+		}
+		if _, _, err := s.NewPrivateKey(); err != nil {
+			t.Fatalf("NewPrivateKey error = %v\n", err)
+		}
+		closeFn()
+	}
+
+	// corrupt every cached file
+	var cachedFiles int
+	err := filepath.WalkDir(cacheDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		cachedFiles++
+		return os.WriteFile(path, []byte("corrupted"), 0600)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cachedFiles == 0 {
+		t.Fatal("compilation cache is empty")
+	}
+
+	wrapper, closeFn, err := NewWrapper(WithCompilationCache(cacheDir))
+	if err != nil {
+		t.Fatalf("NewWrapper should recover from corrupted cache, error = %v\n", err)
+	}
+	defer closeFn()
+
+	s, err := wrapper.NewSession()
+	if err != nil {
+		t.Fatalf("NewSession error = %v\n", err)
+	}
+	if _, _, err := s.NewPrivateKey(); err != nil {
+		t.Fatalf("NewPrivateKey error = %v\n", err)
+	}
+}
+
+func BenchmarkNewWrapper(b *testing.B) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	b.Run("no cache", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, closeFn, err := NewWrapper()
+			if err != nil {
+				b.Fatal(err)
+			}
+			closeFn()
+		}
+	})
+
+	b.Run("cold cache", func(b *testing.B) {
+		cacheDir := b.TempDir()
+		for i := 0; i < b.N; i++ {
+			_, closeFn, err := NewWrapper(WithCompilationCache(filepath.Join(cacheDir, fmt.Sprint(i))))
+			if err != nil {
+				b.Fatal(err)
+			}
+			closeFn()
+		}
+	})
+
+	b.Run("warm cache", func(b *testing.B) {
+		cacheDir := b.TempDir()
+		_, closeFn, err := NewWrapper(WithCompilationCache(cacheDir))
+		if err != nil {
+			b.Fatal(err)
+		}
+		closeFn()
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, closeFn, err := NewWrapper(WithCompilationCache(cacheDir))
+			if err != nil {
+				b.Fatal(err)
+			}
+			closeFn()
+		}
+	})
+}
+
+func TestAleoWrapper_CustomModule(t *testing.T) {
+	hash := sha256.Sum256(wasmBytes)
+	pin := hex.EncodeToString(hash[:])
+
+	modulePath := filepath.Join(t.TempDir(), "aleo_utils.wasm")
+	if err := os.WriteFile(modulePath, wasmBytes, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	emptyModule := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+	tests := []struct {
+		name    string
+		newFn   func() (Wrapper, func(), error)
+		wantErr bool
+		errIs   error
+	}{
+		{
+			name:  "from bytes",
+			newFn: func() (Wrapper, func(), error) { return NewWrapperFromBytes(wasmBytes) },
+		},
+		{
+			name:  "from bytes with pin",
+			newFn: func() (Wrapper, func(), error) { return NewWrapperFromBytes(wasmBytes, WithModuleSHA256(pin)) },
+		},
+		{
+			name:  "from file with pin",
+			newFn: func() (Wrapper, func(), error) { return NewWrapperFromFile(modulePath, WithModuleSHA256(pin)) },
+		},
+		{
+			name: "wrong pin",
+			newFn: func() (Wrapper, func(), error) {
+				return NewWrapperFromBytes(wasmBytes, WithModuleSHA256(hex.EncodeToString(make([]byte, 32))))
+			},
+			wantErr: true,
+			errIs:   ErrModuleHashMismatch,
+		},
+		{
+			name:    "missing file",
+			newFn:   func() (Wrapper, func(), error) { return NewWrapperFromFile(modulePath + ".missing") },
+			wantErr: true,
+			errIs:   fs.ErrNotExist,
+		},
+		{
+			name:    "missing exports",
+			newFn:   func() (Wrapper, func(), error) { return NewWrapperFromBytes(emptyModule) },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapper, closeFn, err := tt.newFn()
+			if tt.wantErr {
+				if err == nil {
+					closeFn()
+					t.Fatal("NewWrapper should fail")
+				}
+				if tt.errIs != nil && !errors.Is(err, tt.errIs) {
+					t.Fatalf("NewWrapper error = %v, want %v", err, tt.errIs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewWrapper error = %v\n", err)
+			}
+			defer closeFn()
+
+			s, err := wrapper.NewSession()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := s.NewPrivateKey(); err != nil {
+				t.Fatalf("NewPrivateKey error = %v\n", err)
+			}
+			t.Logf("module version: %q", wrapper.ModuleVersion())
+		})
+	}
+}
+
+func TestAleoWrapper_Capabilities(t *testing.T) {
+	wrapper, closeFn, err := NewWrapper()
+	if err != nil {
+		t.Fatalf("NewWrapper error = %v\n", err)
+	}
+	defer closeFn()
+
+	// the embedded module supports the ABI handshake
+	caps := wrapper.Capabilities()
+	if caps.ABIVersion != 1 {
+		t.Errorf("Capabilities() ABIVersion = %d, want 1", caps.ABIVersion)
+	}
+	if wrapper.ModuleVersion() == "" {
+		t.Error("ModuleVersion() is empty for the embedded module")
+	}
+	if !reflect.DeepEqual(caps.Networks, []string{"testnet"}) {
+		t.Errorf("Capabilities() Networks = %v, want [testnet]", caps.Networks)
+	}
+	if !reflect.DeepEqual(caps.HashAlgorithms, []string{"poseidon8"}) {
+		t.Errorf("Capabilities() HashAlgorithms = %v, want [poseidon8]", caps.HashAlgorithms)
+	}
+
+	// feature detection must agree with the session behavior
+	s, err := wrapper.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// Verify falls back to pure Go without the verify export
+	if _, err := s.Verify("", nil, ""); errors.Is(err, ErrNotSupported) {
+		t.Errorf("Verify() error = %v, HasFeature(verify) = %v", err, caps.HasFeature(FeatureVerify))
+	}
+}
+
+func TestParseCapabilities(t *testing.T) {
+	caps, err := parseCapabilities("networks=testnet,mainnet\nhash=poseidon8\nsigning=schnorr\nfeatures=\nunknown=1\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Capabilities{
+		Networks:       []string{"testnet", "mainnet"},
+		HashAlgorithms: []string{"poseidon8"},
+		SigningModes:   []string{"schnorr"},
+	}
+	if !reflect.DeepEqual(caps, want) {
+		t.Errorf("parseCapabilities() = %+v, want %+v", caps, want)
+	}
+
+	if _, err := parseCapabilities("networks"); !errors.Is(err, ErrIncompatibleModule) {
+		t.Errorf("parseCapabilities() error = %v, want %v", err, ErrIncompatibleModule)
+	}
+}
+
 func TestAleoWrapper_NewPrivateKey(t *testing.T) {
 	wrapper, closeFn, err := NewWrapper()
 	if err != nil {
@@ -59,6 +307,49 @@ func TestAleoWrapper_NewPrivateKey(t *testing.T) {
 	}
 }
 
+func TestAleoWrapper_RandSource(t *testing.T) {
+	// generates a key and signs a message using a wrapper seeded with the seed
+	generate := func(t *testing.T, seed int64) (key, signature string) {
+		wrapper, closeFn, err := NewWrapper(WithRandSource(mathrand.New(mathrand.NewSource(seed))))
+		if err != nil {
+			t.Fatalf("NewWrapper error = %v\n", err)
+		}
+		defer closeFn()
+
+		s, err := wrapper.NewSession()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+
+		key, _, err = s.NewPrivateKey()
+		if err != nil {
+			t.Fatalf("NewPrivateKey() error = %v", err)
+		}
+
+		signature, err = s.Sign(key, make([]byte, 16))
+		if err != nil {
+			t.Fatalf("Sign() error = %v", err)
+		}
+
+		return key, signature
+	}
+
+	key1, signature1 := generate(t, 1)
+	key2, signature2 := generate(t, 1)
+	if key1 != key2 {
+		t.Errorf("NewPrivateKey() with the same seed = %s, want %s", key2, key1)
+	}
+	if signature1 != signature2 {
+		t.Errorf("Sign() with the same seed = %s, want %s", signature2, signature1)
+	}
+
+	key3, _ := generate(t, 2)
+	if key1 == key3 {
+		t.Error("NewPrivateKey() with different seeds returned the same key")
+	}
+}
+
 func TestAleoWrapper_FormatMessage(t *testing.T) {
 	wrapper, closeFn, err := NewWrapper()
 	if err != nil {
@@ -205,6 +496,13 @@ func TestAleoWrapper_Sign(t *testing.T) {
 		})
 	}
 
+	// a typo in the key is caught before calling the module
+	typo := []byte(key)
+	typo[20] = '0'
+	if _, err := s.Sign(string(typo), make([]byte, 16)); !errors.Is(err, codec.ErrInvalidPrivateKey) {
+		t.Errorf("AleoWrapper.Sign() error = %v, want %v", err, codec.ErrInvalidPrivateKey)
+	}
+
 	s.Close()
 
 	_, err = s.Sign(key, nil)
@@ -213,7 +511,146 @@ func TestAleoWrapper_Sign(t *testing.T) {
 	}
 }
 
-func TestAleoWrapper_RecoverMessage(t *testing.T) {
+func TestAleoWrapper_SignWithOptions(t *testing.T) {
+	for _, selfCheck := range []bool{true, false} {
+		t.Run(fmt.Sprintf("self check %v", selfCheck), func(t *testing.T) {
+			wrapper, closeFn, err := NewWrapper(WithSignatureSelfCheck(selfCheck))
+			if err != nil {
+				t.Fatalf("NewWrapper error = %v\n", err)
+			}
+			defer closeFn()
+
+			s, err := wrapper.NewSession()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+
+			key, address, err := s.NewPrivateKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			message := make([]byte, 16)
+
+			signatures := make([]string, 0, 3)
+			for _, opts := range []SignOptions{{SkipSelfCheck: false}, {SkipSelfCheck: true}} {
+				signature, err := s.SignWithOptions(key, message, opts)
+				if err != nil {
+					t.Fatalf("SignWithOptions(%+v) error = %v", opts, err)
+				}
+				signatures = append(signatures, signature)
+			}
+
+			signature, err := s.Sign(key, message)
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+			signatures = append(signatures, signature)
+
+			for _, signature := range signatures {
+				valid, err := s.Verify(address, message, signature)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !valid {
+					t.Errorf("signature %s is invalid", signature)
+				}
+			}
+
+			if _, err := s.SignWithOptions("invalid key", message, SignOptions{SkipSelfCheck: true}); err == nil {
+				t.Error("SignWithOptions() should fail with invalid key")
+			}
+		})
+	}
+}
+
+// deterministic nonce test vectors, the key is the same as in cmd/sgx
+var deterministicNonceVectors = []struct {
+	name      string
+	key       string
+	address   string
+	message   []byte
+	signature string
+}{
+	{
+		name:      "zero message",
+		key:       "APrivateKey1zkpEem71u7U75h5VodKNgyR37aGJBj4ZTgagCHm3qsuz5PU",
+		address:   "aleo1vz6e7yyv9anm7xpnl02nzwz5qdgvaypx428gpx5vdjnhgle64cpsld55le",
+		message:   make([]byte, 16),
+		signature: "sign10cky89z5ju5sewznl35v7ty4x56mfx839lenlqlrnxpajuqf0uphznzrz034lm5a2dw32f2c2hzt0m32c0s4ay9tppsmqyxhmug2wqwyy907fdlanswgvn6kyrvggtg6c0d8r7558egrhtakudetpdf5pc6ksk7g2gvfh80c9wzu87w46t0zdzdkeq4elydn62lcwnmk7sxsqymlz39",
+	},
+	{
+		name:      "u128 bytes",
+		key:       "APrivateKey1zkpEem71u7U75h5VodKNgyR37aGJBj4ZTgagCHm3qsuz5PU",
+		address:   "aleo1vz6e7yyv9anm7xpnl02nzwz5qdgvaypx428gpx5vdjnhgle64cpsld55le",
+		message:   []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10},
+		signature: "sign13zjx9ns93eqzar3dsde8tarzw39l8yc5gylpy47lx5a8mff02vq7yjn7v2c8rmnu3lu56wnwjrwmvalu9mmadgtklxtdpgrwsdw35qxyy907fdlanswgvn6kyrvggtg6c0d8r7558egrhtakudetpdf5pc6ksk7g2gvfh80c9wzu87w46t0zdzdkeq4elydn62lcwnmk7sxsq3nfhxd",
+	},
+	{
+		name:      "max u128",
+		key:       "APrivateKey1zkpEem71u7U75h5VodKNgyR37aGJBj4ZTgagCHm3qsuz5PU",
+		address:   "aleo1vz6e7yyv9anm7xpnl02nzwz5qdgvaypx428gpx5vdjnhgle64cpsld55le",
+		message:   bytes.Repeat([]byte{0xff}, 16),
+		signature: "sign1ftuea3ewz0r5lqflph2ymcrpwpp9tdnfp22enpx56xyf5atcfvqkkyw65cp0ye9lh4e3xtsmq6d3ypdwdzafn733x3rccgpkhqlukqxyy907fdlanswgvn6kyrvggtg6c0d8r7558egrhtakudetpdf5pc6ksk7g2gvfh80c9wzu87w46t0zdzdkeq4elydn62lcwnmk7sxsql9327l",
+	},
+}
+
+func TestAleoWrapper_DeterministicNonce(t *testing.T) {
+	newSession := func(t *testing.T) Session {
+		wrapper, closeFn, err := NewWrapper(WithDeterministicNonce(true))
+		if err != nil {
+			t.Fatalf("NewWrapper error = %v\n", err)
+		}
+		t.Cleanup(closeFn)
+
+		s, err := wrapper.NewSession()
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(s.Close)
+
+		return s
+	}
+
+	// signatures must be reproducible by independent wrappers
+	s1 := newSession(t)
+	s2 := newSession(t)
+
+	seen := make(map[string]string)
+	for _, tt := range deterministicNonceVectors {
+		t.Run(tt.name, func(t *testing.T) {
+			signature, err := s1.Sign(tt.key, tt.message)
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+			if signature != tt.signature {
+				t.Errorf("Sign() = %s, want %s", signature, tt.signature)
+			}
+
+			again, err := s2.SignWithOptions(tt.key, tt.message, SignOptions{DeterministicNonce: true, SkipSelfCheck: true})
+			if err != nil {
+				t.Fatalf("SignWithOptions() error = %v", err)
+			}
+			if again != signature {
+				t.Errorf("SignWithOptions() = %s, want %s", again, signature)
+			}
+
+			if other, ok := seen[signature]; ok {
+				t.Errorf("signature of %s is the same as of %s", tt.name, other)
+			}
+			seen[signature] = tt.name
+
+			valid, err := s1.Verify(tt.address, tt.message, signature)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !valid {
+				t.Errorf("signature %s is invalid", signature)
+			}
+		})
+	}
+
+	// randomized nonces are still the default
 	wrapper, closeFn, err := NewWrapper()
 	if err != nil {
 		t.Fatalf("NewWrapper error = %v\n", err)
@@ -224,95 +661,2560 @@ func TestAleoWrapper_RecoverMessage(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer s.Close()
 
-	type args struct {
-		message []byte
+	vector := deterministicNonceVectors[0]
+	first, err := s.Sign(vector.key, vector.message)
+	if err != nil {
+		t.Fatal(err)
 	}
-	tests := []struct {
-		name        string
-		args        args
-		formatFirst bool
-		wantErr     bool
-	}{
-		{
-			name: "invalid - nil",
-			args: args{
-				message: nil,
-			},
-			formatFirst: false,
-			wantErr:     true,
-		},
-		{
-			name: "invalid - not a formatted message",
-			args: args{
-				message: make([]byte, 10),
-			},
-			formatFirst: false,
-			wantErr:     true,
-		},
-		{
-			name: "invalid - not a struct",
-			args: args{
-				message: []byte("123u128"),
-			},
-			formatFirst: false,
-			wantErr:     true,
-		},
-		{
-			name: "invalid - not a correct struct",
-			args: args{
-				message: []byte("{ a: 1u128 }"),
-			},
-			formatFirst: false,
-			wantErr:     true,
-		},
-		{
-			name: "valid",
-			args: args{
-				message: make([]byte, 16),
-			},
-			formatFirst: true,
-			wantErr:     false,
-		},
+	second, err := s.Sign(vector.key, vector.message)
+	if err != nil {
+		t.Fatal(err)
 	}
-	for _, tt := range tests {
+	if first == second {
+		t.Error("Sign() without deterministic nonces returned the same signature twice")
+	}
+}
+
+func TestDeterministicNonceVectors(t *testing.T) {
+	for _, tt := range deterministicNonceVectors {
 		t.Run(tt.name, func(t *testing.T) {
-			var formattedMessage, gotMessage []byte
-			var err error
-			if tt.formatFirst {
-				formattedMessage, err = s.FormatMessage(tt.args.message, 32)
-				if err != nil {
-					t.Fatal(err)
-					return
-				}
-				log.Println(string(formattedMessage))
-			} else {
-				formattedMessage = tt.args.message
-				err = nil
+			address, err := SignatureAddress(tt.signature)
+			if err != nil {
+				t.Fatalf("SignatureAddress() error = %v", err)
 			}
-
-			gotMessage, err = s.RecoverMessage(formattedMessage)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("AleoWrapper.RecoverMessage() error = %v, wantErr %v", err, tt.wantErr)
-				return
+			if address != tt.address {
+				t.Errorf("SignatureAddress() = %s, want %s", address, tt.address)
 			}
+
+			valid, err := VerifySignature(tt.address, tt.message, tt.signature)
 			if err != nil {
-				return
+				t.Fatalf("VerifySignature() error = %v", err)
 			}
-
-			padding := make([]byte, 16*32*32-len(tt.args.message))
-			wantMessage := append(tt.args.message, padding...)
-
-			if !reflect.DeepEqual(gotMessage, wantMessage) {
-				t.Errorf("AleoWrapper.RecoverMessage() = %v, want %v", gotMessage, wantMessage)
+			if !valid {
+				t.Errorf("signature %s is invalid", tt.signature)
 			}
 		})
 	}
+}
+
+func TestSignatureAddress(t *testing.T) {
+	// signed by APrivateKey1zkpGtmQCAPmXtvrybJ55wq4Fre3fJXutXFraPqNA2CP9aPq
+	const (
+		signature = "sign1y44uay8rmvt4je3u4k4yqrl268lpca2dj07uyxlhdy3x8vhffup4rmlhdyxsh6zrhvfsd8yv77fepjw2wv20p6phq6e3xzr2yl3fkqmkhpkxc4yf3rz4njwgnh5cak0htu3d3walg2cgr4fxdsr8efu9pmgc97n3hnnzjxel47w2djv72rlyu8s34s7jdkmxg9jk0ptthkdsxqla883"
+		address   = "aleo1p3ncc278u59l4xhql6xnqm5r80zr2t374c63487patjpmzwy0ups7w0vhy"
+	)
+
+	got, err := SignatureAddress(signature)
+	if err != nil {
+		t.Fatalf("SignatureAddress() error = %v", err)
+	}
+	if got != address {
+		t.Errorf("SignatureAddress() = %s, want %s", got, address)
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	// the same signature as in TestSignatureAddress, the message is a zero u128
+	const (
+		signature = "sign1y44uay8rmvt4je3u4k4yqrl268lpca2dj07uyxlhdy3x8vhffup4rmlhdyxsh6zrhvfsd8yv77fepjw2wv20p6phq6e3xzr2yl3fkqmkhpkxc4yf3rz4njwgnh5cak0htu3d3walg2cgr4fxdsr8efu9pmgc97n3hnnzjxel47w2djv72rlyu8s34s7jdkmxg9jk0ptthkdsxqla883"
+		address   = "aleo1p3ncc278u59l4xhql6xnqm5r80zr2t374c63487patjpmzwy0ups7w0vhy"
+		// the address of the key in cmd/sgx
+		otherAddress = "aleo1vz6e7yyv9anm7xpnl02nzwz5qdgvaypx428gpx5vdjnhgle64cpsld55le"
+	)
+	message := make([]byte, 16)
+
+	parsed, err := ParseSignature(signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a valid signature with a different challenge
+	tampered := *parsed
+	tampered.Challenge[0] ^= 1
+
+	tests := []struct {
+		name      string
+		address   string
+		message   []byte
+		signature string
+		want      bool
+	}{
+		{"valid", address, message, signature, true},
+		{"trailing message bytes are ignored", address, append(bytes.Clone(message), 1), signature, true},
+		{"wrong message", address, append([]byte{1}, message[1:]...), signature, false},
+		{"wrong address", otherAddress, message, signature, false},
+		{"wrong challenge", address, message, tampered.String(), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, err := VerifySignature(tt.address, tt.message, tt.signature)
+			if err != nil {
+				t.Fatalf("VerifySignature() error = %v", err)
+			}
+			if valid != tt.want {
+				t.Errorf("VerifySignature() = %v, want %v", valid, tt.want)
+			}
+		})
+	}
+
+	if _, err := VerifySignature(address, message[:15], signature); err == nil {
+		t.Error("VerifySignature() should fail with a short message")
+	}
+	if _, err := VerifySignature(address, message, "sign1invalid"); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("VerifySignature() error = %v, want %v", err, ErrInvalidSignature)
+	}
+	if _, err := VerifySignature("aleo1invalid", message, signature); !errors.Is(err, codec.ErrInvalidAddress) {
+		t.Errorf("VerifySignature() error = %v, want %v", err, codec.ErrInvalidAddress)
+	}
+}
+
+func TestAleoWrapper_VerifySignature(t *testing.T) {
+	wrapper, closeFn, err := NewWrapper()
+	if err != nil {
+		t.Fatalf("NewWrapper error = %v\n", err)
+	}
+	defer closeFn()
+
+	s, err := wrapper.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	key, address, err := s.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// signatures of the module must be valid in pure Go
+	rng := mathrand.New(mathrand.NewSource(26))
+	for i := 0; i < 4; i++ {
+		message := make([]byte, 16)
+		rng.Read(message)
+
+		signature, err := s.Sign(key, message)
+		if err != nil {
+			t.Fatalf("Sign() error = %v", err)
+		}
+
+		valid, err := VerifySignature(address, message, signature)
+		if err != nil {
+			t.Fatalf("VerifySignature() error = %v", err)
+		}
+		if !valid {
+			t.Errorf("VerifySignature(%x, %s) = false, want true", message, signature)
+		}
+	}
+}
+
+func TestParseSignature(t *testing.T) {
+	wrapper, closeFn, err := NewWrapper()
+	if err != nil {
+		t.Fatalf("NewWrapper error = %v\n", err)
+	}
+	defer closeFn()
+
+	s, err := wrapper.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	key, address, err := s.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signature, err := s.Sign(key, make([]byte, 16))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParseSignature(signature)
+	if err != nil {
+		t.Fatalf("ParseSignature() error = %v", err)
+	}
+	if parsed.String() != signature {
+		t.Errorf("Signature.String() = %s, want %s", parsed.String(), signature)
+	}
+
+	// an x-coordinate, which doesn't belong to the prime-order subgroup
+	var invalidX [fieldElementSize]byte
+	for invalidX[0] = 2; ; invalidX[0]++ {
+		if _, err := literal.GroupFromBytesLE(invalidX[:]); err != nil {
+			break
+		}
+	}
+
+	// replace the last checksum character
+	wrongChecksum := signature[:len(signature)-1] + "q"
+	if signature[len(signature)-1] == 'q' {
+		wrongChecksum = signature[:len(signature)-1] + "p"
+	}
+
+	tamper := func(modify func(sig *Signature)) string {
+		sig := *parsed
+		modify(&sig)
+		return sig.String()
+	}
+
+	tests := []struct {
+		name      string
+		signature string
+	}{
+		{
+			name:      "empty",
+			signature: "",
+		},
+		{
+			name:      "wrong checksum",
+			signature: wrongChecksum,
+		},
+		{
+			name:      "wrong prefix",
+			signature: codec.Bech32mEncode("sigh", parsed.Bytes()),
+		},
+		{
+			name:      "uppercase",
+			signature: strings.ToUpper(signature),
+		},
+		{
+			name:      "challenge out of range",
+			signature: tamper(func(sig *Signature) { sig.Challenge = [fieldElementSize]byte{31: 0xff} }),
+		},
+		{
+			name:      "response out of range",
+			signature: tamper(func(sig *Signature) { sig.Response = [fieldElementSize]byte{31: 0xff} }),
+		},
+		{
+			name:      "invalid public key",
+			signature: tamper(func(sig *Signature) { sig.ComputeKey.PkSig = invalidX }),
+		},
+		{
+			name:      "invalid public randomizer",
+			signature: tamper(func(sig *Signature) { sig.ComputeKey.PrSig = invalidX }),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseSignature(tt.signature); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("ParseSignature() error = %v, want %v", err, ErrInvalidSignature)
+			}
+		})
+	}
+
+	signer, err := SignatureAddress(signature)
+	if err != nil {
+		t.Fatalf("SignatureAddress() error = %v", err)
+	}
+	if signer != address {
+		t.Errorf("SignatureAddress() = %s, want %s", signer, address)
+	}
+
+	if _, err := SignatureAddress("sign1invalid"); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("SignatureAddress() error = %v, want %v", err, ErrInvalidSignature)
+	}
+}
+
+// accounts of the record fixtures, the view keys are derived from the private keys
+var recordAccounts = []struct {
+	key     string
+	viewKey string
+	address string
+}{
+	{
+		key:     "APrivateKey1zkpGtmQCAPmXtvrybJ55wq4Fre3fJXutXFraPqNA2CP9aPq",
+		viewKey: "AViewKey1royLHJYPfSyXSaoFPrvenAAQyVHVAWrFhvzn2yD8B6rt",
+		address: "aleo1p3ncc278u59l4xhql6xnqm5r80zr2t374c63487patjpmzwy0ups7w0vhy",
+	},
+	{
+		key:     "APrivateKey1zkpEem71u7U75h5VodKNgyR37aGJBj4ZTgagCHm3qsuz5PU",
+		viewKey: "AViewKey1fnoh7paNtdmza6k256Bb24FNRRfLmW7SRGUxQHrA9bwE",
+		address: "aleo1vz6e7yyv9anm7xpnl02nzwz5qdgvaypx428gpx5vdjnhgle64cpsld55le",
+	},
+}
+
+// recordFixture is a credits record of the first record account. It's encrypted with recordRandomizer, so
+// the decrypted record has recordNonce, which is the generator multiplied by the randomizer.
+const (
+	recordFixture    = "{ owner: aleo1p3ncc278u59l4xhql6xnqm5r80zr2t374c63487patjpmzwy0ups7w0vhy.private, microcredits: 1500000u64.private, _nonce: 0group.public }"
+	recordRandomizer = "1234scalar"
+	recordNonce      = "2753979376767684106655212440228660014706854219494851416647472325877148306368group"
+)
+
+// recordEntries returns the entries of a Leo record without whitespace, e.g. `microcredits:100u64.private`
+func recordEntries(record string) []string {
+	record = strings.Join(strings.Fields(record), "")
+	record = strings.TrimSuffix(strings.TrimPrefix(record, "{"), "}")
+
+	return strings.Split(record, ",")
+}
+
+func TestRecordAccounts(t *testing.T) {
+	for _, account := range recordAccounts {
+		buf, err := codec.DecodeViewKey(account.viewKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		viewKey, err := literal.ScalarFromBytesLE(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// an address is the generator multiplied by the view key
+		if address := literal.NewAddress(literal.Generator().Mul(viewKey)).String(); address != account.address {
+			t.Errorf("address of %s = %s, want %s", account.viewKey, address, account.address)
+		}
+	}
+
+	randomizer, err := literal.ParseScalar(recordRandomizer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nonce := literal.Generator().Mul(randomizer).String(); nonce != recordNonce {
+		t.Errorf("nonce = %s, want %s", nonce, recordNonce)
+	}
+}
+
+func TestAleoWrapper_Records(t *testing.T) {
+	wrapper, closeFn, err := NewWrapper()
+	if err != nil {
+		t.Fatalf("NewWrapper error = %v\n", err)
+	}
+	defer closeFn()
+
+	s, err := wrapper.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if !wrapper.Capabilities().HasFeature(FeatureRecords) || !wrapper.Capabilities().HasFeature(FeatureRecordEncryption) {
+		t.Fatal("embedded module doesn't support records")
+	}
+
+	owner, other := recordAccounts[0], recordAccounts[1]
+
+	ciphertext, err := s.EncryptRecord(recordFixture, recordRandomizer)
+	if err != nil {
+		t.Fatalf("EncryptRecord() error = %v", err)
+	}
+
+	decrypted, err := s.DecryptRecord(owner.viewKey, ciphertext)
+	if err != nil {
+		t.Fatalf("DecryptRecord() error = %v", err)
+	}
+	want := []string{
+		"owner:" + owner.address + ".private",
+		"microcredits:1500000u64.private",
+		"_nonce:" + recordNonce + ".public",
+	}
+	for _, entry := range want {
+		if !slices.Contains(recordEntries(decrypted), entry) {
+			t.Errorf("DecryptRecord() = %s, want entry %s", decrypted, entry)
+		}
+	}
+
+	if isOwner, err := s.IsOwner(owner.viewKey, ciphertext); err != nil || !isOwner {
+		t.Errorf("IsOwner() = %v, %v for the owner, want true", isOwner, err)
+	}
+	if isOwner, err := s.IsOwner(other.viewKey, ciphertext); err != nil || isOwner {
+		t.Errorf("IsOwner() = %v, %v for another account, want false", isOwner, err)
+	}
+	if _, err := s.DecryptRecord(other.viewKey, ciphertext); err == nil {
+		t.Error("DecryptRecord() should fail with a view key of another account")
+	}
+
+	viewKey, err := codec.EncodeViewKey(make([]byte, codec.ViewKeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := codec.Bech32mEncode(codec.RecordPrefix, make([]byte, 64))
+
+	tests := []struct {
+		name       string
+		viewKey    string
+		ciphertext string
+		wantErr    error
+	}{
+		{
+			name:       "invalid view key",
+			viewKey:    "AViewKey1" + strings.Repeat("0", VIEW_KEY_SIZE-9),
+			ciphertext: record,
+			wantErr:    codec.ErrInvalidViewKey,
+		},
+		{
+			name:       "invalid ciphertext",
+			viewKey:    viewKey,
+			ciphertext: "record1",
+			wantErr:    codec.ErrInvalidRecord,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.DecryptRecord(tt.viewKey, tt.ciphertext); !errors.Is(err, tt.wantErr) {
+				t.Errorf("DecryptRecord() error = %v, want %v", err, tt.wantErr)
+			}
+			if _, err := s.IsOwner(tt.viewKey, tt.ciphertext); !errors.Is(err, tt.wantErr) {
+				t.Errorf("IsOwner() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// a well-formed string, which is not a record, is rejected by the module
+	if _, err := s.DecryptRecord(viewKey, record); err == nil {
+		t.Error("DecryptRecord() should fail on a malformed record")
+	}
+	if _, err := s.IsOwner(viewKey, record); err == nil {
+		t.Error("IsOwner() should fail on a malformed record")
+	}
+}
+
+func TestAleoWrapper_RecordEncryption(t *testing.T) {
+	wrapper, closeFn, err := NewWrapper()
+	if err != nil {
+		t.Fatalf("NewWrapper error = %v\n", err)
+	}
+	defer closeFn()
+
+	s, err := wrapper.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if !wrapper.Capabilities().HasFeature(FeatureRecordEncryption) || !wrapper.Capabilities().HasFeature(FeatureRecordSerialNumbers) {
+		t.Fatal("embedded module doesn't support record encryption")
+	}
+
+	owner, other := recordAccounts[1], recordAccounts[0]
+	record := fmt.Sprintf("{ owner: %s.private, microcredits: 100u64.private, _nonce: 0group.public }", owner.address)
+
+	ciphertext, err := s.EncryptRecord(record, recordRandomizer)
+	if err != nil {
+		t.Fatalf("EncryptRecord() error = %v", err)
+	}
+	if err := codec.ValidateRecordCiphertext(ciphertext); err != nil {
+		t.Errorf("EncryptRecord() = %s, error = %v", ciphertext, err)
+	}
+
+	// encryption is deterministic for the same randomizer
+	again, err := s.EncryptRecord(record, recordRandomizer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != ciphertext {
+		t.Errorf("EncryptRecord() = %s, want %s", again, ciphertext)
+	}
+
+	another, err := s.EncryptRecord(record, "2scalar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if another == ciphertext {
+		t.Error("EncryptRecord() with different randomizers returned the same ciphertext")
+	}
+
+	if _, err := s.EncryptRecord(record, "1field"); err == nil {
+		t.Error("EncryptRecord() should fail with invalid randomizer")
+	}
+
+	// the record round trips with the nonce derived from the randomizer
+	decrypted, err := s.DecryptRecord(owner.viewKey, ciphertext)
+	if err != nil {
+		t.Fatalf("DecryptRecord() error = %v", err)
+	}
+	want := []string{
+		"owner:" + owner.address + ".private",
+		"microcredits:100u64.private",
+		"_nonce:" + recordNonce + ".public",
+	}
+	for _, entry := range want {
+		if !slices.Contains(recordEntries(decrypted), entry) {
+			t.Errorf("DecryptRecord() = %s, want entry %s", decrypted, entry)
+		}
+	}
+
+	if isOwner, err := s.IsOwner(owner.viewKey, ciphertext); err != nil || !isOwner {
+		t.Errorf("IsOwner() = %v, %v for the owner, want true", isOwner, err)
+	}
+	if isOwner, err := s.IsOwner(other.viewKey, ciphertext); err != nil || isOwner {
+		t.Errorf("IsOwner() = %v, %v for another account, want false", isOwner, err)
+	}
+
+	// the commitment covers the nonce, so the decrypted record has the commitment of the record with the nonce
+	commitment, err := s.RecordCommitment(decrypted, "credits.aleo", "credits")
+	if err != nil {
+		t.Fatalf("RecordCommitment() error = %v", err)
+	}
+	if !strings.HasSuffix(commitment, "field") {
+		t.Errorf("RecordCommitment() = %s, want a field literal", commitment)
+	}
+	withNonce := strings.Replace(record, "0group.public", recordNonce+".public", 1)
+	if got, err := s.RecordCommitment(withNonce, "credits.aleo", "credits"); err != nil || got != commitment {
+		t.Errorf("RecordCommitment() = %s, %v, want %s", got, err, commitment)
+	}
+	if got, err := s.RecordCommitment(record, "credits.aleo", "credits"); err != nil || got == commitment {
+		t.Errorf("RecordCommitment() = %s, %v for another nonce, want a different commitment", got, err)
+	}
+
+	serialNumber, err := s.RecordSerialNumber(owner.key, commitment)
+	if err != nil {
+		t.Fatalf("RecordSerialNumber() error = %v", err)
+	}
+	if otherSerialNumber, err := s.RecordSerialNumber(other.key, commitment); err != nil || otherSerialNumber == serialNumber {
+		t.Errorf("RecordSerialNumber() = %s, %v for another account, want a different serial number", otherSerialNumber, err)
+	}
+
+	// the tag is the same for the private key and the view key of the owner
+	tag, err := s.RecordTag(owner.key, commitment)
+	if err != nil {
+		t.Fatalf("RecordTag() error = %v", err)
+	}
+	if viewKeyTag, err := s.RecordTag(owner.viewKey, commitment); err != nil || viewKeyTag != tag {
+		t.Errorf("RecordTag() = %s, %v with the view key, want %s", viewKeyTag, err, tag)
+	}
+	if serialNumber == tag {
+		t.Error("RecordSerialNumber() and RecordTag() returned the same value")
+	}
+}
+
+// transitionVector is a transition of the first record account with the transition secret key 77scalar. The transition
+// public key is the generator multiplied by the secret key, and the transition view key is the x-coordinate of
+// the address multiplied by the secret key.
+var transitionVector = struct {
+	viewKey string
+	address string
+	tsk     string
+	tpk     string
+	tvk     string
+}{
+	viewKey: "AViewKey1royLHJYPfSyXSaoFPrvenAAQyVHVAWrFhvzn2yD8B6rt",
+	address: "aleo1p3ncc278u59l4xhql6xnqm5r80zr2t374c63487patjpmzwy0ups7w0vhy",
+	tsk:     "77scalar",
+	tpk:     "553662499844311452649255544396551125732008249121275309471027512253492484091group",
+	tvk:     "2196222529302424885672050802572341871896160496139166934437273172602978479784field",
+}
+
+func TestTransitionVector(t *testing.T) {
+	tsk, err := literal.ParseScalar(transitionVector.tsk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tpk := literal.Generator().Mul(tsk).String(); tpk != transitionVector.tpk {
+		t.Errorf("tpk = %s, want %s", tpk, transitionVector.tpk)
+	}
+
+	address, err := literal.ParseAddress(transitionVector.address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the signer computes the tvk from the tsk, the recipient from the tpk and the view key
+	tvk := strings.TrimSuffix(address.Group().Mul(tsk).String(), "group") + "field"
+	if tvk != transitionVector.tvk {
+		t.Errorf("tvk = %s, want %s", tvk, transitionVector.tvk)
+	}
+
+	buf, err := codec.DecodeViewKey(transitionVector.viewKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	viewKey, err := literal.ScalarFromBytesLE(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tpk, err := literal.ParseGroup(transitionVector.tpk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tvk := strings.TrimSuffix(tpk.Mul(viewKey).String(), "group") + "field"; tvk != transitionVector.tvk {
+		t.Errorf("tvk = %s, want %s", tvk, transitionVector.tvk)
+	}
+}
+
+func TestAleoWrapper_Transitions(t *testing.T) {
+	wrapper, closeFn, err := NewWrapper()
+	if err != nil {
+		t.Fatalf("NewWrapper error = %v\n", err)
+	}
+	defer closeFn()
+
+	s, err := wrapper.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	ciphertext := codec.Bech32mEncode(codec.CiphertextPrefix, make([]byte, 64))
+
+	if _, err := s.DecryptTransitionOutput("1field", "credits.aleo", "transfer_private", 3, -1, ciphertext); err == nil {
+		t.Error("DecryptTransitionOutput() should fail with negative index")
+	}
+
+	if !wrapper.Capabilities().HasFeature(FeatureTransitions) {
+		t.Fatal("embedded module doesn't support transitions")
+	}
+
+	tvk, err := s.TransitionViewKey(transitionVector.viewKey, transitionVector.tpk)
+	if err != nil {
+		t.Fatalf("TransitionViewKey() error = %v", err)
+	}
+	if tvk != transitionVector.tvk {
+		t.Errorf("TransitionViewKey() = %s, want %s", tvk, transitionVector.tvk)
+	}
+
+	if _, err := s.TransitionViewKey(transitionVector.viewKey, "1group"); err == nil {
+		t.Error("TransitionViewKey() should fail with an invalid transition public key")
+	}
+
+	tests := []struct {
+		name       string
+		index      int
+		ciphertext string
+		wantErr    error
+	}{
+		{
+			name:       "index out of range",
+			index:      1 << 16,
+			ciphertext: ciphertext,
+		},
+		{
+			name:       "not a ciphertext",
+			ciphertext: codec.Bech32mEncode(codec.RecordPrefix, make([]byte, 64)),
+			wantErr:    codec.ErrInvalidCiphertext,
+		},
+		{
+			name:       "malformed ciphertext",
+			ciphertext: ciphertext,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.DecryptTransitionInput(tvk, "credits.aleo", "transfer_private", tt.index, tt.ciphertext)
+			if err == nil {
+				t.Fatal("DecryptTransitionInput() should fail")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("DecryptTransitionInput() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAleoWrapper_RecoverMessage(t *testing.T) {
+	wrapper, closeFn, err := NewWrapper()
+	if err != nil {
+		t.Fatalf("NewWrapper error = %v\n", err)
+	}
+	defer closeFn()
+
+	s, err := wrapper.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		message []byte
+	}
+	tests := []struct {
+		name        string
+		args        args
+		formatFirst bool
+		wantErr     bool
+	}{
+		{
+			name: "invalid - nil",
+			args: args{
+				message: nil,
+			},
+			formatFirst: false,
+			wantErr:     true,
+		},
+		{
+			name: "invalid - not a formatted message",
+			args: args{
+				message: make([]byte, 10),
+			},
+			formatFirst: false,
+			wantErr:     true,
+		},
+		{
+			name: "invalid - not a struct",
+			args: args{
+				message: []byte("123u128"),
+			},
+			formatFirst: false,
+			wantErr:     true,
+		},
+		{
+			name: "invalid - not a correct struct",
+			args: args{
+				message: []byte("{ a: 1u128 }"),
+			},
+			formatFirst: false,
+			wantErr:     true,
+		},
+		{
+			name: "valid",
+			args: args{
+				message: make([]byte, 16),
+			},
+			formatFirst: true,
+			wantErr:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var formattedMessage, gotMessage []byte
+			var err error
+			if tt.formatFirst {
+				formattedMessage, err = s.FormatMessage(tt.args.message, 32)
+				if err != nil {
+					t.Fatal(err)
+					return
+				}
+				log.Println(string(formattedMessage))
+			} else {
+				formattedMessage = tt.args.message
+				err = nil
+			}
+
+			gotMessage, err = s.RecoverMessage(formattedMessage)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AleoWrapper.RecoverMessage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			padding := make([]byte, 16*32*32-len(tt.args.message))
+			wantMessage := append(tt.args.message, padding...)
+
+			if !reflect.DeepEqual(gotMessage, wantMessage) {
+				t.Errorf("AleoWrapper.RecoverMessage() = %v, want %v", gotMessage, wantMessage)
+			}
+		})
+	}
+
+	s.Close()
+
+	_, err = s.RecoverMessage(nil)
+	if !errors.Is(err, ErrNoModule) {
+		t.Fatal("session should return error on any function call after it was closed")
+	}
+}
+
+func TestAleoWrapper_RuntimeModes(t *testing.T) {
+	message := []byte("btc/usd = 1.0")
+	owner := recordAccounts[0]
+
+	// run calls every session method and returns the formatted results and errors by method name. The seeded
+	// randomness and deterministic nonces make keys, signatures and requests reproducible, so the results must be
+	// identical in both runtime modes.
+	run := func(t *testing.T, mode RuntimeMode) map[string]string {
+		wrapper, closeFn, err := NewWrapper(
+			WithRuntimeMode(mode),
+			WithRandSource(mathrand.New(mathrand.NewSource(28))),
+			WithDeterministicNonce(true),
+		)
+		if err != nil {
+			t.Fatalf("NewWrapper error = %v\n", err)
+		}
+		defer closeFn()
+
+		s, err := wrapper.NewSession()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+
+		results := make(map[string]string)
+		record := func(method string, value any, err error) {
+			results[method] = fmt.Sprintf("%v (error: %v)", value, err)
+		}
+		// the original methods must succeed in every mode
+		require := func(method string, err error) {
+			if err != nil {
+				t.Fatalf("%s error = %v, runtime mode %s\n", method, err, mode)
+			}
+		}
+
+		key, address, err := s.NewPrivateKey()
+		require("NewPrivateKey", err)
+		record("NewPrivateKey", key+" "+address, err)
+
+		formattedMessage, err := s.FormatMessage(message, 2)
+		require("FormatMessage", err)
+		record("FormatMessage", formattedMessage, err)
+
+		recoveredMessage, err := s.RecoverMessage(formattedMessage)
+		require("RecoverMessage", err)
+		record("RecoverMessage", recoveredMessage, err)
+
+		hash, err := s.HashMessage(formattedMessage)
+		require("HashMessage", err)
+		record("HashMessage", hash, err)
+
+		hashString, err := s.HashMessageToString(formattedMessage)
+		require("HashMessageToString", err)
+		record("HashMessageToString", hashString, err)
+
+		signature, err := s.Sign(key, hash)
+		require("Sign", err)
+		record("Sign", signature, err)
+
+		valid, err := s.Verify(address, hash, signature)
+		require("Verify", err)
+		if !valid {
+			t.Fatalf("Verify signature is invalid, runtime mode %s\n", mode)
+		}
+		record("Verify", valid, err)
+
+		signature, err = s.SignWithOptions(key, hash, SignOptions{SkipSelfCheck: true, DeterministicNonce: true})
+		record("SignWithOptions", signature, err)
+
+		ciphertext, err := s.EncryptRecord(recordFixture, recordRandomizer)
+		record("EncryptRecord", ciphertext, err)
+
+		decrypted, err := s.DecryptRecord(owner.viewKey, ciphertext)
+		record("DecryptRecord", decrypted, err)
+
+		isOwner, err := s.IsOwner(owner.viewKey, ciphertext)
+		record("IsOwner", isOwner, err)
+
+		commitment, err := s.RecordCommitment(decrypted, "credits.aleo", "credits")
+		record("RecordCommitment", commitment, err)
+
+		serialNumber, err := s.RecordSerialNumber(owner.key, commitment)
+		record("RecordSerialNumber", serialNumber, err)
+
+		tag, err := s.RecordTag(owner.viewKey, commitment)
+		record("RecordTag", tag, err)
+
+		tvk, err := s.TransitionViewKey(transitionVector.viewKey, transitionVector.tpk)
+		record("TransitionViewKey", tvk, err)
+
+		plaintext, err := s.DecryptTransitionInput(transitionVector.tvk, "credits.aleo", "transfer_private", 0, ciphertext)
+		record("DecryptTransitionInput", plaintext, err)
+
+		plaintext, err = s.DecryptTransitionOutput(transitionVector.tvk, "credits.aleo", "transfer_private", 3, 0, ciphertext)
+		record("DecryptTransitionOutput", plaintext, err)
+
+		programAddress, err := s.ProgramAddress("credits.aleo")
+		record("ProgramAddress", programAddress, err)
+
+		keyID, err := s.MappingKeyID("credits.aleo", "account", owner.address)
+		record("MappingKeyID", keyID, err)
+
+		fields, err := s.PlaintextToFields("{ a: 1u8, b: [2u16, 3u16] }")
+		record("PlaintextToFields", fields, err)
+
+		request, err := s.SignRequest(owner.key, "credits.aleo", "transfer_public", []string{address, "100u64"}, []string{"address.public", "u64.public"})
+		record("SignRequest", request, err)
+
+		authorization, err := s.SignFeePublic(owner.key, "123field", 1000, 10)
+		if authorization != nil {
+			record("SignFeePublic", authorization.String(), err)
+		} else {
+			record("SignFeePublic", nil, err)
+		}
+
+		tx, err := s.ParseTransaction([]byte(checkTransactionFixture))
+		record("ParseTransaction", tx, err)
+
+		failures, err := s.CheckTransactionConsistency([]byte(checkTransactionFixture))
+		record("CheckTransactionConsistency", failures, err)
+
+		formattedHash, err := s.FormatAndHash(message, 2)
+		record("FormatAndHash", formattedHash, err)
+
+		signedMessage, err := s.FormatHashSign(key, message, 2)
+		record("FormatHashSign", signedMessage, err)
+
+		hashes, err := s.HashMessageBatch([][]byte{formattedMessage, message})
+		record("HashMessageBatch", hashes, err)
+
+		signatures, err := s.SignBatch(key, [][]byte{hash, []byte("1")})
+		record("SignBatch", signatures, err)
+
+		handle, err := s.LoadKey(key)
+		record("LoadKey", handle != nil, err)
+		if err == nil {
+			signature, err = s.SignWithHandle(handle, hash)
+			record("SignWithHandle", signature, err)
+
+			signature, err = s.SignWithHandleOptions(handle, hash, SignOptions{SkipSelfCheck: true, DeterministicNonce: true})
+			record("SignWithHandleOptions", signature, err)
+		}
+
+		return results
+	}
+
+	compiler := run(t, RuntimeModeCompiler)
+	interpreter := run(t, RuntimeModeInterpreter)
+
+	if len(compiler) != len(interpreter) {
+		t.Errorf("compiler called %d methods, interpreter called %d", len(compiler), len(interpreter))
+	}
+	for method, want := range compiler {
+		if got := interpreter[method]; got != want {
+			t.Errorf("%s results differ between compiler and interpreter:\ncompiler:    %s\ninterpreter: %s", method, want, got)
+		}
+	}
+
+	_, _, err := NewWrapper(WithRuntimeMode(RuntimeMode(100)))
+	if err == nil {
+		t.Error("NewWrapper should fail with unknown runtime mode")
+	}
+}
+
+func TestAleoWrapper_FormatAndHash(t *testing.T) {
+	wrapper, closeFn, err := NewWrapper()
+	if err != nil {
+		t.Fatalf("NewWrapper error = %v\n", err)
+	}
+	defer closeFn()
+
+	// the one-shot results must come from the module, not from the fallback to the three-step path
+	if !wrapper.Capabilities().HasFeature(FeatureFormatAndHash) {
+		t.Fatal("embedded module doesn't support format and hash")
+	}
+
+	s, err := wrapper.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	key, address, err := s.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		message []byte
+		chunks  int
+		wantErr bool
+	}{
+		{name: "empty message", message: nil, chunks: 1},
+		{name: "message", message: []byte("btc/usd = 1.0"), chunks: 2},
+		{name: "full message", message: make([]byte, MESSAGE_FORMAT_BLOCK_SIZE*32), chunks: 32},
+		{name: "message is too long", message: make([]byte, MESSAGE_FORMAT_BLOCK_SIZE+1), chunks: 1, wantErr: true},
+		{name: "invalid chunks", message: nil, chunks: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.FormatAndHash(tt.message, tt.chunks)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FormatAndHash() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			signed, signErr := s.FormatHashSign(key, tt.message, tt.chunks)
+			if (signErr != nil) != tt.wantErr {
+				t.Fatalf("FormatHashSign() error = %v, wantErr %v", signErr, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			// the result must be identical to the three-step path
+			formattedMessage, err := s.FormatMessage(tt.message, tt.chunks)
+			if err != nil {
+				t.Fatal(err)
+			}
+			hashString, err := s.HashMessageToString(formattedMessage)
+			if err != nil {
+				t.Fatal(err)
+			}
+			hash, err := s.HashMessage(formattedMessage)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := FormattedHash{
+				FormattedMessage: formattedMessage,
+				HashString:       hashString,
+				Hash:             hash,
+			}
+
+			if !reflect.DeepEqual(*got, want) {
+				t.Errorf("FormatAndHash() = %+v, want %+v", *got, want)
+			}
+			if !reflect.DeepEqual(signed.FormattedHash, want) {
+				t.Errorf("FormatHashSign() = %+v, want %+v", signed.FormattedHash, want)
+			}
+			if len(signed.Signature) != SIGNATURE_SIZE {
+				t.Errorf("FormatHashSign() signature = %q, want %d characters", signed.Signature, SIGNATURE_SIZE)
+			}
+
+			valid, err := s.Verify(address, hash, signed.Signature)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !valid {
+				t.Error("FormatHashSign() signature is invalid")
+			}
+		})
+	}
+}
+
+func TestAleoWrapper_FormatHashSignOptions(t *testing.T) {
+	wrapper, closeFn, err := NewWrapper(WithDeterministicNonce(true), WithSignatureSelfCheck(false))
+	if err != nil {
+		t.Fatalf("NewWrapper error = %v\n", err)
+	}
+	defer closeFn()
+
+	if !wrapper.Capabilities().HasFeature(FeatureFormatAndHash) {
+		t.Fatal("embedded module doesn't support format and hash")
+	}
+
+	s, err := wrapper.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	key, address, err := s.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signed, err := s.FormatHashSign(key, []byte("btc/usd = 1.0"), 1)
+	if err != nil {
+		t.Fatalf("FormatHashSign() error = %v", err)
+	}
+
+	// the wrapper options are passed to the module, so the deterministic signature matches Sign
+	want, err := s.Sign(key, signed.Hash)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if signed.Signature != want {
+		t.Errorf("FormatHashSign() signature = %s, want %s", signed.Signature, want)
+	}
+
+	if valid, err := s.Verify(address, signed.Hash, signed.Signature); err != nil || !valid {
+		t.Errorf("FormatHashSign() signature is invalid, error = %v", err)
+	}
+}
+
+func TestAleoWrapper_Batch(t *testing.T) {
+	wrapper, closeFn, err := NewWrapper()
+	if err != nil {
+		t.Fatalf("NewWrapper error = %v\n", err)
+	}
+	defer closeFn()
+
+	s, err := wrapper.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	key, address, err := s.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var messages [][]byte
+	for _, data := range []string{"btc/usd = 1.0", "eth/usd = 2.0", ""} {
+		formattedMessage, err := s.FormatMessage([]byte(data), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		messages = append(messages, formattedMessage)
+	}
+	// invalid item must not fail the whole batch
+	messages = append(messages, []byte("not a formatted message"))
+
+	hashes, err := s.HashMessageBatch(messages)
+	if err != nil {
+		t.Fatalf("HashMessageBatch() error = %v", err)
+	}
+	if len(hashes) != len(messages) {
+		t.Fatalf("HashMessageBatch() returned %d results, want %d", len(hashes), len(messages))
+	}
+
+	var hashMessages [][]byte
+	for i, message := range messages {
+		wantHash, wantErr := s.HashMessage(message)
+		if (hashes[i].Err != nil) != (wantErr != nil) {
+			t.Errorf("HashMessageBatch() item %d error = %v, HashMessage error = %v", i, hashes[i].Err, wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(hashes[i].Hash, wantHash) {
+			t.Errorf("HashMessageBatch() item %d = %x, want %x", i, hashes[i].Hash, wantHash)
+		}
+		if wantErr == nil {
+			hashMessages = append(hashMessages, wantHash)
+		}
+	}
+	// invalid item, u128 needs 16 bytes
+	hashMessages = append(hashMessages, []byte{1, 2, 3})
+
+	signatures, err := s.SignBatch(key, hashMessages)
+	if err != nil {
+		t.Fatalf("SignBatch() error = %v", err)
+	}
+	if len(signatures) != len(hashMessages) {
+		t.Fatalf("SignBatch() returned %d results, want %d", len(signatures), len(hashMessages))
+	}
+
+	for i, result := range signatures {
+		if i == len(signatures)-1 {
+			if result.Err == nil {
+				t.Error("SignBatch() should fail for an invalid message")
+			}
+			continue
+		}
+		if result.Err != nil {
+			t.Errorf("SignBatch() item %d error = %v", i, result.Err)
+			continue
+		}
+
+		valid, err := s.Verify(address, hashMessages[i], result.Signature)
+		if err != nil && !errors.Is(err, ErrNotSupported) {
+			t.Fatal(err)
+		}
+		if err == nil && !valid {
+			t.Errorf("SignBatch() item %d signature is invalid", i)
+		}
+	}
+
+	if _, err := s.SignBatch("invalid key", hashMessages); err == nil {
+		t.Error("SignBatch() should fail with invalid key")
+	}
+}
+
+func TestAleoWrapper_SignBatchOptions(t *testing.T) {
+	wrapper, closeFn, err := NewWrapper(WithDeterministicNonce(true), WithSignatureSelfCheck(false))
+	if err != nil {
+		t.Fatalf("NewWrapper error = %v\n", err)
+	}
+	defer closeFn()
+
+	// the batch must be signed by the module, not by the fallback loop
+	if !wrapper.Capabilities().HasFeature(FeatureBatch) {
+		t.Fatal("embedded module doesn't support batches")
+	}
+
+	s, err := wrapper.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	key, address, err := s.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := [][]byte{make([]byte, 16), bytes.Repeat([]byte{0xff}, 16)}
+	results, err := s.SignBatch(key, messages)
+	if err != nil {
+		t.Fatalf("SignBatch() error = %v", err)
+	}
+
+	// the batch uses the wrapper options, so deterministic signatures match Sign
+	for i, message := range messages {
+		if results[i].Err != nil {
+			t.Fatalf("SignBatch() item %d error = %v", i, results[i].Err)
+		}
+
+		want, err := s.Sign(key, message)
+		if err != nil {
+			t.Fatalf("Sign() error = %v", err)
+		}
+		if results[i].Signature != want {
+			t.Errorf("SignBatch() item %d = %s, want %s", i, results[i].Signature, want)
+		}
+
+		if valid, err := s.Verify(address, message, results[i].Signature); err != nil || !valid {
+			t.Errorf("SignBatch() item %d is invalid, error = %v", i, err)
+		}
+	}
+}
+
+func TestAleoWrapper_KeyHandle(t *testing.T) {
+	wrapper, closeFn, err := NewWrapper()
+	if err != nil {
+		t.Fatalf("NewWrapper error = %v\n", err)
+	}
+	defer closeFn()
+
+	s, err := wrapper.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, address, err := s.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handle, err := s.LoadKey(key)
+	if err != nil {
+		t.Fatalf("LoadKey() error = %v", err)
+	}
+
+	if _, err := s.LoadKey("12345678901234567890123456789012345678901234567890123456789"); err == nil {
+		t.Error("LoadKey() should fail with invalid key")
+	}
+
+	message := make([]byte, 16)
+	signature, err := s.SignWithHandle(handle, message)
+	if err != nil {
+		t.Fatalf("SignWithHandle() error = %v", err)
+	}
+
+	valid, err := s.Verify(address, message, signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !valid {
+		t.Error("SignWithHandle() signature is invalid")
+	}
+
+	for _, opts := range []SignOptions{{}, {SkipSelfCheck: true}} {
+		signature, err := s.SignWithHandleOptions(handle, message, opts)
+		if err != nil {
+			t.Fatalf("SignWithHandleOptions(%+v) error = %v", opts, err)
+		}
+		if valid, err := s.Verify(address, message, signature); err != nil || !valid {
+			t.Errorf("SignWithHandleOptions(%+v) signature is invalid, error = %v", opts, err)
+		}
+	}
+
+	// the handle honors the signing options
+	deterministic := SignOptions{DeterministicNonce: true}
+	withHandle, err := s.SignWithHandleOptions(handle, message, deterministic)
+	if err != nil {
+		t.Fatalf("SignWithHandleOptions() error = %v", err)
+	}
+	withKey, err := s.SignWithOptions(key, message, deterministic)
+	if err != nil {
+		t.Fatalf("SignWithOptions(key) error = %v", err)
+	}
+	if withHandle != withKey {
+		t.Errorf("deterministic signature with handle = %s, with key = %s", withHandle, withKey)
+	}
+
+	other, err := wrapper.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.SignWithHandle(handle, message); err == nil {
+		t.Error("SignWithHandle() should fail with a handle of another session")
+	}
+	other.Close()
+
+	if err := handle.Release(); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if err := handle.Release(); !errors.Is(err, ErrKeyReleased) {
+		t.Errorf("Release() error = %v, want %v", err, ErrKeyReleased)
+	}
+	if _, err := s.SignWithHandle(handle, message); !errors.Is(err, ErrKeyReleased) {
+		t.Errorf("SignWithHandle() error = %v, want %v", err, ErrKeyReleased)
+	}
+
+	handle, err = s.LoadKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.Close()
+
+	if _, err := s.SignWithHandle(handle, message); !errors.Is(err, ErrNoModule) {
+		t.Errorf("SignWithHandle() error = %v, want %v", err, ErrNoModule)
+	}
+}
+
+func TestSessionArena(t *testing.T) {
+	wrapper, closeFn, err := NewWrapper()
+	if err != nil {
+		t.Fatalf("NewWrapper error = %v\n", err)
+	}
+	defer closeFn()
+
+	s, err := wrapper.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	session := s.(*aleoWrapperSession)
+
+	// empty arguments on a fresh session still need a valid pointer
+	if err := session.resetArena(0); err != nil {
+		t.Fatalf("resetArena(0) error = %v", err)
+	}
+	if session.arena.ptr == 0 || session.arena.size < minArenaSize {
+		t.Fatalf("arena = %+v, want a non-null pointer and at least %d bytes", session.arena, minArenaSize)
+	}
+
+	ptr, err := session.putString("")
+	if err != nil || ptr == 0 {
+		t.Errorf("putString(\"\") = %d, %v, want a non-null pointer", ptr, err)
+	}
+
+	// the arena is reused while the arguments fit
+	arena := session.arena
+	if err := session.resetArena(minArenaSize); err != nil {
+		t.Fatalf("resetArena() error = %v", err)
+	}
+	if session.arena != arena {
+		t.Errorf("arena = %+v, want %+v", session.arena, arena)
+	}
+
+	if err := session.resetArena(minArenaSize + 1); err != nil {
+		t.Fatalf("resetArena() error = %v", err)
+	}
+	if session.arena.size < minArenaSize+1 {
+		t.Errorf("arena size = %d, want at least %d", session.arena.size, minArenaSize+1)
+	}
+}
+
+func TestUnpackFields(t *testing.T) {
+	packed := []byte{3, 0, 0, 0, 'a', 'b', 'c', 0, 0, 0, 0, 1, 0, 0, 0, 'd'}
+
+	fields, ok := unpackFields(packed, 3)
+	if !ok {
+		t.Fatal("unpackFields() failed")
+	}
+	want := [][]byte{[]byte("abc"), {}, []byte("d")}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("unpackFields() = %q, want %q", fields, want)
+	}
+
+	if _, ok := unpackFields(packed, 2); ok {
+		t.Error("unpackFields() should fail on unexpected number of fields")
+	}
+	if _, ok := unpackFields(packed[:len(packed)-1], 3); ok {
+		t.Error("unpackFields() should fail on truncated buffer")
+	}
+}
+
+func TestAleoWrapper_SessionAllocations(t *testing.T) {
+	wrapper, closeFn, err := NewWrapper()
+	if err != nil {
+		t.Fatalf("NewWrapper error = %v\n", err)
+	}
+	defer closeFn()
+
+	s, err := wrapper.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	key, _, err := s.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message := []byte("btc/usd = 1.0")
+	formattedMessage, err := s.FormatMessage(message, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := s.HashMessage(formattedMessage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every call is expected to allocate only its result on the Go side
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{name: "FormatMessage", fn: func() { s.FormatMessage(message, 1) }},
+		{name: "HashMessage", fn: func() { s.HashMessage(formattedMessage) }},
+		{name: "Sign", fn: func() { s.Sign(key, hash) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if allocs := testing.AllocsPerRun(3, tt.fn); allocs > 1 {
+				t.Errorf("%s allocations = %v, want at most 1", tt.name, allocs)
+			}
+		})
+	}
+}
+
+func newBenchmarkSession(b *testing.B) Session {
+	_, s := newBenchmarkWrapper(b)
+
+	return s
+}
+
+// newBenchmarkWrapper is like newBenchmarkSession, but also returns the wrapper for feature detection.
+func newBenchmarkWrapper(b *testing.B) (Wrapper, Session) {
+	log.SetOutput(io.Discard)
+	b.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	wrapper, closeFn, err := NewWrapper()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(closeFn)
+
+	s, err := wrapper.NewSession()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(s.Close)
+
+	return wrapper, s
+}
+
+func BenchmarkSession_FormatMessage(b *testing.B) {
+	s := newBenchmarkSession(b)
+	message := make([]byte, MESSAGE_FORMAT_BLOCK_SIZE*8)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.FormatMessage(message, 8); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSession_HashMessage(b *testing.B) {
+	s := newBenchmarkSession(b)
+	formattedMessage, err := s.FormatMessage([]byte("btc/usd = 1.0"), 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.HashMessage(formattedMessage); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSession_Sign(b *testing.B) {
+	wrapper, s := newBenchmarkWrapper(b)
+	key, _, err := s.NewPrivateKey()
+	if err != nil {
+		b.Fatal(err)
+	}
+	hash := make([]byte, 16)
+
+	b.Run("self check", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := s.Sign(key, hash); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("no self check", func(b *testing.B) {
+		// without sign options the signature is always self checked, which would measure the same as above
+		if !wrapper.Capabilities().HasFeature(FeatureSignOptions) {
+			b.Fatal("embedded module doesn't support sign options")
+		}
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := s.SignWithOptions(key, hash, SignOptions{SkipSelfCheck: true}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkSession_SignBatch(b *testing.B) {
+	wrapper, s := newBenchmarkWrapper(b)
+	key, _, err := s.NewPrivateKey()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	messages := make([][]byte, 16)
+	for i := range messages {
+		messages[i] = make([]byte, 16)
+		messages[i][0] = byte(i)
+	}
+
+	b.Run("loop", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, message := range messages {
+				if _, err := s.Sign(key, message); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	b.Run("batch", func(b *testing.B) {
+		// without the batch export SignBatch falls back to the same loop as above
+		if !wrapper.Capabilities().HasFeature(FeatureBatch) {
+			b.Fatal("embedded module doesn't support batches")
+		}
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := s.SignBatch(key, messages); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestAleoWrapper_Programs(t *testing.T) {
+	wrapper, closeFn, err := NewWrapper()
+	if err != nil {
+		t.Fatalf("NewWrapper error = %v\n", err)
+	}
+	defer closeFn()
+
+	s, err := wrapper.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if !wrapper.Capabilities().HasFeature(FeaturePrograms) {
+		t.Fatal("embedded module doesn't support programs")
+	}
+
+	address, err := s.ProgramAddress("credits.aleo")
+	if err != nil {
+		t.Fatalf("ProgramAddress() error = %v", err)
+	}
+	if address != "aleo1lqmly7ez2k48ajf5hs92ulphaqr05qm4n8qwzj8v0yprmasgpqgsez59gg" {
+		t.Errorf("ProgramAddress() = %s, want credits.aleo address", address)
+	}
+
+	if _, err := s.ProgramAddress("credits"); err == nil {
+		t.Error("ProgramAddress() should fail with invalid program ID")
+	}
+
+	key := "aleo1vz6e7yyv9anm7xpnl02nzwz5qdgvaypx428gpx5vdjnhgle64cpsld55le"
+	keyID, err := s.MappingKeyID("credits.aleo", "account", key)
+	if err != nil {
+		t.Fatalf("MappingKeyID() error = %v", err)
+	}
+	if !strings.HasSuffix(keyID, "field") {
+		t.Errorf("MappingKeyID() = %s, want a field literal", keyID)
+	}
+
+	otherKeyID, err := s.MappingKeyID("credits.aleo", "bonded", key)
+	if err != nil {
+		t.Fatalf("MappingKeyID() error = %v", err)
+	}
+	if keyID == otherKeyID {
+		t.Error("MappingKeyID() should depend on the mapping name")
+	}
+
+	otherKeyID, err = s.MappingKeyID("credits.aleo", "account", recordAccounts[0].address)
+	if err != nil {
+		t.Fatalf("MappingKeyID() error = %v", err)
+	}
+	if keyID == otherKeyID {
+		t.Error("MappingKeyID() should depend on the key")
+	}
+
+	// the key is hashed as a plaintext, so whitespace doesn't change the key ID
+	again, err := s.MappingKeyID("credits.aleo", "account", " "+key+" ")
+	if err != nil {
+		t.Fatalf("MappingKeyID() error = %v", err)
+	}
+	if again != keyID {
+		t.Errorf("MappingKeyID() = %s, want %s", again, keyID)
+	}
+
+	if _, err := s.MappingKeyID("credits.aleo", "account", "not a value"); err == nil {
+		t.Error("MappingKeyID() should fail with invalid key")
+	}
+
+	for _, plaintext := range []string{"{ a: 1u8, b: 2field }", key, "[1u128, 2u128, 3u128]"} {
+		fields, err := s.PlaintextToFields(plaintext)
+		if err != nil {
+			t.Fatalf("PlaintextToFields(%s) error = %v", plaintext, err)
+		}
+
+		// the pure Go encoding is checked against the module hashes
+		want, err := poseidon.PlaintextFields(plaintext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantStrings := make([]string, 0, len(want))
+		for _, f := range want {
+			wantStrings = append(wantStrings, f.String())
+		}
+		if !reflect.DeepEqual(fields, wantStrings) {
+			t.Errorf("PlaintextToFields(%s) = %v, want %v", plaintext, fields, wantStrings)
+		}
+	}
+}
+
+func TestAleoWrapper_SignRequest(t *testing.T) {
+	owner := recordAccounts[0]
+	inputs := []string{recordAccounts[1].address, "100u64"}
+	inputTypes := []string{"address.public", "u64.public"}
+
+	type signedRequest struct {
+		Signer   string `json:"signer"`
+		Program  string `json:"program"`
+		Function string `json:"function"`
+		InputIDs []struct {
+			Type string `json:"type"`
+			ID   string `json:"id"`
+		} `json:"input_ids"`
+		Inputs []string `json:"inputs"`
+		TVK    string   `json:"tvk"`
+		TCM    string   `json:"tcm"`
+	}
+
+	// sign signs the same request with the same seeded randomness, so the transition keys and input IDs are
+	// reproducible
+	sign := func(t *testing.T) signedRequest {
+		wrapper, closeFn, err := NewWrapper(WithRandSource(mathrand.New(mathrand.NewSource(44))))
+		if err != nil {
+			t.Fatalf("NewWrapper error = %v\n", err)
+		}
+		defer closeFn()
+
+		if !wrapper.Capabilities().HasFeature(FeatureRequests) {
+			t.Fatal("embedded module doesn't support requests")
+		}
+
+		s, err := wrapper.NewSession()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+
+		if _, err := s.SignRequest(owner.key, "credits.aleo", "transfer_public", inputs, inputTypes[:1]); err == nil {
+			t.Error("SignRequest() should fail when inputs don't match input types")
+		}
+
+		typo := []byte(owner.key)
+		typo[20] = '0'
+		if _, err := s.SignRequest(string(typo), "credits.aleo", "transfer_public", inputs, inputTypes); !errors.Is(err, codec.ErrInvalidPrivateKey) {
+			t.Errorf("SignRequest() error = %v, want %v", err, codec.ErrInvalidPrivateKey)
+		}
+
+		if _, err := s.SignRequest(owner.key, "credits.aleo", "transfer_public", inputs, []string{"address.public", "u64"}); err == nil {
+			t.Error("SignRequest() should fail with invalid input type")
+		}
+
+		request, err := s.SignRequest(owner.key, "credits.aleo", "transfer_public", inputs, inputTypes)
+		if err != nil {
+			t.Fatalf("SignRequest() error = %v", err)
+		}
+
+		var decoded signedRequest
+		if err := json.Unmarshal([]byte(request), &decoded); err != nil {
+			t.Fatalf("SignRequest() returned invalid JSON: %v", err)
+		}
+
+		return decoded
+	}
+
+	request := sign(t)
+
+	if request.Signer != owner.address || request.Program != "credits.aleo" || request.Function != "transfer_public" {
+		t.Errorf("SignRequest() = %+v, doesn't match the arguments", request)
+	}
+	if !slices.Equal(request.Inputs, inputs) {
+		t.Errorf("SignRequest() inputs = %v, want %v", request.Inputs, inputs)
+	}
+
+	// the transition commitment is the Poseidon2 hash of the transition view key
+	var tvk field.Base
+	if _, err := tvk.SetString(request.TVK); err != nil {
+		t.Fatalf("SignRequest() tvk = %s: %v", request.TVK, err)
+	}
+	if tcm := poseidon.Hash2([]field.Base{tvk}); tcm.String() != request.TCM {
+		t.Errorf("SignRequest() tcm = %s, want %s", request.TCM, tcm.String())
+	}
+
+	if len(request.InputIDs) != len(inputs) {
+		t.Fatalf("SignRequest() returned %d input IDs, want %d", len(request.InputIDs), len(inputs))
+	}
+	for i, input := range request.InputIDs {
+		if input.Type != "public" {
+			t.Errorf("SignRequest() input %d type = %s, want public", i, input.Type)
+		}
+		if _, err := literal.ParseField(input.ID); err != nil {
+			t.Errorf("SignRequest() input %d ID = %s: %v", i, input.ID, err)
+		}
+	}
+
+	// the same key, inputs and randomness produce the same transition keys and input IDs
+	if again := sign(t); !reflect.DeepEqual(again, request) {
+		t.Errorf("SignRequest() with the same randomness = %+v, want %+v", again, request)
+	}
+}
+
+func TestFeePublicInputs(t *testing.T) {
+	tests := []struct {
+		name        string
+		executionID string
+		baseFee     uint64
+		priorityFee uint64
+		want        []string
+		wantErr     bool
+	}{
+		{
+			name:        "fees",
+			executionID: "7294503422075521328263128785591428591520489716484574862683297101478396329478field",
+			baseFee:     1000,
+			priorityFee: 25,
+			want:        []string{"1000u64", "25u64", "7294503422075521328263128785591428591520489716484574862683297101478396329478field"},
+		},
+		{
+			name:        "no fees",
+			executionID: "0field",
+			want:        []string{"0u64", "0u64", "0field"},
+		},
+		{
+			name:        "max fee",
+			executionID: "1field",
+			baseFee:     math.MaxUint64 - 1,
+			priorityFee: 1,
+			want:        []string{"18446744073709551614u64", "1u64", "1field"},
+		},
+		{
+			name:        "fee overflow",
+			executionID: "1field",
+			baseFee:     math.MaxUint64,
+			priorityFee: 1,
+			wantErr:     true,
+		},
+		{
+			name:        "transaction ID",
+			executionID: "at1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqsqq3t8j",
+			wantErr:     true,
+		},
+		{
+			name:        "no digits",
+			executionID: "field",
+			wantErr:     true,
+		},
+		{
+			name:        "negative",
+			executionID: "-1field",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := feePublicInputs(tt.executionID, tt.baseFee, tt.priorityFee)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("feePublicInputs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("feePublicInputs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// feePublicTransition is the expected fee_public transition of the first record account paying 1000 + 25 microcredits
+// for the execution 1field. The IDs, the transition public key and the commitments depend on the request randomness.
+var feePublicTransition = Transition{
+	Program:  "credits.aleo",
+	Function: "fee_public",
+	Inputs: []TransitionIO{
+		{Type: "public", Value: "1000u64"},
+		{Type: "public", Value: "25u64"},
+		{Type: "public", Value: "1field"},
+	},
+	Outputs: []TransitionIO{
+		{Type: "future", Value: "{ program_id: credits.aleo, function_name: fee_public, arguments: [ aleo1p3ncc278u59l4xhql6xnqm5r80zr2t374c63487patjpmzwy0ups7w0vhy, 1025u64 ] }"},
+	},
+}
+
+// withoutRandomness clears the values of a transition, which depend on the request randomness, and removes
+// whitespace from the input and output values
+func withoutRandomness(transition Transition) Transition {
+	clean := func(ios []TransitionIO) []TransitionIO {
+		result := make([]TransitionIO, 0, len(ios))
+		for _, io := range ios {
+			result = append(result, TransitionIO{Type: io.Type, Value: strings.Join(strings.Fields(io.Value), "")})
+		}
+		return result
+	}
+
+	return Transition{
+		Program:  transition.Program,
+		Function: transition.Function,
+		Inputs:   clean(transition.Inputs),
+		Outputs:  clean(transition.Outputs),
+	}
+}
+
+func TestDecodeAuthorization(t *testing.T) {
+	transition := `{"id":"au1id","program":"credits.aleo","function":"fee_public",` +
+		`"inputs":[{"type":"public","id":"1field","value":"1000u64"},{"type":"public","id":"2field","value":"25u64"},{"type":"public","id":"3field","value":"1field"}],` +
+		`"outputs":[{"type":"future","id":"4field","value":"{ program_id: credits.aleo, function_name: fee_public, arguments: [ aleo1p3ncc278u59l4xhql6xnqm5r80zr2t374c63487patjpmzwy0ups7w0vhy, 1025u64 ] }"}],` +
+		`"tpk":"5group","tcm":"6field","scm":"7field"}`
+	request := `{"signer":"aleo1p3ncc278u59l4xhql6xnqm5r80zr2t374c63487patjpmzwy0ups7w0vhy","program":"credits.aleo","function":"fee_public"}`
+	authorization := fmt.Sprintf(`{"requests":[%s],"transitions":[%s]}`, request, transition)
+
+	got, err := decodeAuthorization(authorization)
+	if err != nil {
+		t.Fatalf("decodeAuthorization() error = %v", err)
+	}
+	if got.String() != authorization {
+		t.Errorf("Authorization.String() = %s, want %s", got.String(), authorization)
+	}
+	if !reflect.DeepEqual(got.Requests, []string{request}) {
+		t.Errorf("decodeAuthorization() requests = %v, want %s", got.Requests, request)
+	}
+	if len(got.Transitions) != 1 {
+		t.Fatalf("decodeAuthorization() got %d transitions, want 1", len(got.Transitions))
+	}
+	if got.Transitions[0].ID != "au1id" || got.Transitions[0].TPK != "5group" {
+		t.Errorf("decodeAuthorization() transition = %+v", got.Transitions[0])
+	}
+	if !reflect.DeepEqual(withoutRandomness(got.Transitions[0]), withoutRandomness(feePublicTransition)) {
+		t.Errorf("decodeAuthorization() transition = %+v, want %+v", got.Transitions[0], feePublicTransition)
+	}
+
+	inputs, err := feePublicInputs("1field", 1000, 25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isFeePublicTransition(&got.Transitions[0], inputs) {
+		t.Error("isFeePublicTransition() = false for the requested fee")
+	}
+	inputs, err = feePublicInputs("2field", 1000, 25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isFeePublicTransition(&got.Transitions[0], inputs) {
+		t.Error("isFeePublicTransition() = true for another execution")
+	}
+
+	for _, invalid := range []string{"", "{}", `{"requests":[],"transitions":[]}`, fmt.Sprintf(`{"requests":[%s],"transitions":[]}`, request)} {
+		if _, err := decodeAuthorization(invalid); err == nil {
+			t.Errorf("decodeAuthorization(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestAleoWrapper_SignFeePublic(t *testing.T) {
+	wrapper, closeFn, err := NewWrapper()
+	if err != nil {
+		t.Fatalf("NewWrapper error = %v\n", err)
+	}
+	defer closeFn()
+
+	s, err := wrapper.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if _, err := s.SignFeePublic(recordAccounts[0].key, "at1abc", 1000, 0); !errors.Is(err, ErrInvalidExecutionID) {
+		t.Errorf("SignFeePublic() error = %v, want %v", err, ErrInvalidExecutionID)
+	}
+
+	if !wrapper.Capabilities().HasFeature(FeatureFeeAuthorizations) {
+		t.Fatal("embedded module doesn't support fee authorizations")
+	}
+
+	authorization, err := s.SignFeePublic(recordAccounts[0].key, "1field", 1000, 25)
+	if err != nil {
+		t.Fatalf("SignFeePublic() error = %v", err)
+	}
+
+	if len(authorization.Transitions) != 1 {
+		t.Fatalf("SignFeePublic() got %d transitions, want 1", len(authorization.Transitions))
+	}
+	transition := authorization.Transitions[0]
+	if !reflect.DeepEqual(withoutRandomness(transition), withoutRandomness(feePublicTransition)) {
+		t.Errorf("SignFeePublic() transition = %+v, want %+v", transition, feePublicTransition)
+	}
+	if !strings.HasPrefix(transition.ID, "au1") || !strings.HasSuffix(transition.TPK, "group") || !strings.HasSuffix(transition.TCM, "field") {
+		t.Errorf("SignFeePublic() transition = %+v, want an ID, a tpk and a tcm", transition)
+	}
+
+	var request struct {
+		Signer   string `json:"signer"`
+		Program  string `json:"program"`
+		Function string `json:"function"`
+		TCM      string `json:"tcm"`
+	}
+	if err := json.Unmarshal([]byte(authorization.Requests[0]), &request); err != nil {
+		t.Fatalf("SignFeePublic() returned invalid request JSON: %v", err)
+	}
+	if request.Signer != recordAccounts[0].address || request.Program != "credits.aleo" || request.Function != "fee_public" {
+		t.Errorf("SignFeePublic() request = %s, want a fee_public request of the signer", authorization.Requests[0])
+	}
+	// the transition is built from the request
+	if request.TCM != transition.TCM {
+		t.Errorf("SignFeePublic() request tcm = %s, transition tcm = %s", request.TCM, transition.TCM)
+	}
 
-	s.Close()
+	if !json.Valid([]byte(authorization.String())) {
+		t.Errorf("Authorization.String() = %s, want JSON", authorization.String())
+	}
+}
 
-	_, err = s.RecoverMessage(nil)
-	if !errors.Is(err, ErrNoModule) {
-		t.Fatal("session should return error on any function call after it was closed")
+func TestDecodeTransaction(t *testing.T) {
+	transition := `{"id":"au1p6w4tkcyx4ruw0lzw2vwmn7a2dfkhs8jzh6mnm5ulhwh2fnaqq8sa5ed3h","program":"credits.aleo","function":"%s",` +
+		`"inputs":[{"type":"public","id":"1field","value":"%s"},{"type":"record","id":"2field","tag":"3field"}],` +
+		`"outputs":[{"type":"future","id":"4field","value":"{ program_id: credits.aleo }"}],` +
+		`"tpk":"5group","tcm":"6field","scm":"7field"}`
+
+	execution := fmt.Sprintf(`{"type":"execute","id":"at1id","execution":{"transitions":[%s],"global_state_root":"sr1root","proof":"proof1"},`+
+		`"fee":{"transition":%s,"global_state_root":"sr1root","proof":"proof1"}}`,
+		fmt.Sprintf(transition, "transfer_public", "100u64"), fmt.Sprintf(transition, "fee_public", "25u64"))
+
+	tx, err := decodeTransaction([]byte(execution), []byte("1000"), []byte("25"))
+	if err != nil {
+		t.Fatalf("decodeTransaction() error = %v", err)
+	}
+
+	if tx.ID != "at1id" || tx.Type != TransactionTypeExecute {
+		t.Errorf("decodeTransaction() ID = %s, Type = %s", tx.ID, tx.Type)
+	}
+	if len(tx.Transitions) != 1 {
+		t.Fatalf("decodeTransaction() got %d transitions, want 1", len(tx.Transitions))
+	}
+
+	wantTransition := Transition{
+		ID:       "au1p6w4tkcyx4ruw0lzw2vwmn7a2dfkhs8jzh6mnm5ulhwh2fnaqq8sa5ed3h",
+		Program:  "credits.aleo",
+		Function: "transfer_public",
+		Inputs: []TransitionIO{
+			{Type: "public", ID: "1field", Value: "100u64"},
+			{Type: "record", ID: "2field", Tag: "3field"},
+		},
+		Outputs: []TransitionIO{
+			{Type: "future", ID: "4field", Value: "{ program_id: credits.aleo }"},
+		},
+		TPK: "5group",
+		TCM: "6field",
+		SCM: "7field",
+	}
+	if !reflect.DeepEqual(tx.Transitions[0], wantTransition) {
+		t.Errorf("decodeTransaction() transition = %+v, want %+v", tx.Transitions[0], wantTransition)
+	}
+
+	if tx.Fee == nil {
+		t.Fatal("decodeTransaction() fee is nil")
+	}
+	if tx.Fee.BaseAmount != 1000 || tx.Fee.PriorityAmount != 25 || tx.Fee.Transition.Function != "fee_public" {
+		t.Errorf("decodeTransaction() fee = %+v", tx.Fee)
+	}
+
+	deployment := `{"type":"deploy","id":"at1id","owner":{"address":"aleo1owner","signature":"sign1"},` +
+		`"deployment":{"edition":1,"program":"program hello.aleo;","verifying_keys":[]}}`
+
+	tx, err = decodeTransaction([]byte(deployment), nil, nil)
+	if err != nil {
+		t.Fatalf("decodeTransaction() error = %v", err)
+	}
+	if tx.Type != TransactionTypeDeploy || tx.Owner != "aleo1owner" || tx.Program != "program hello.aleo;" || tx.Fee != nil {
+		t.Errorf("decodeTransaction() deployment = %+v", tx)
+	}
+
+	if _, err := decodeTransaction([]byte(execution), []byte(""), []byte("25")); err == nil {
+		t.Error("decodeTransaction() should fail with invalid fee")
+	}
+}
+
+func TestAleoWrapper_ParseTransaction(t *testing.T) {
+	wrapper, closeFn, err := NewWrapper()
+	if err != nil {
+		t.Fatalf("NewWrapper error = %v\n", err)
+	}
+	defer closeFn()
+
+	if !wrapper.Capabilities().HasFeature(FeatureTransactions) {
+		t.Fatal("embedded module doesn't support transactions")
+	}
+	// the transaction IDs of the fixture are recomputed with CheckTransactionConsistency
+	if !wrapper.Capabilities().HasFeature(FeatureTransactionChecks) {
+		t.Fatal("embedded module doesn't support transaction checks")
+	}
+
+	s, err := wrapper.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// the fee publishes its amounts, so ParseTransaction can read them
+	fee := strings.NewReplacer(
+		`{"type": "public", "id": "8field"}`, `{"type": "public", "id": "8field", "value": "1000u64"}`,
+		`{"type": "public", "id": "9field"}`, `{"type": "public", "id": "9field", "value": "25u64"}`,
+	).Replace(checkTransactionFee)
+	transaction, _ := consistentTransaction(t, s, strings.Replace(checkTransactionFixture, `"execution"`, `"fee": `+fee+`, "execution"`, 1))
+
+	claimed := new(rawTransaction)
+	if err := json.Unmarshal([]byte(transaction), claimed); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := s.ParseTransaction([]byte(transaction))
+	if err != nil {
+		t.Fatalf("ParseTransaction() error = %v", err)
+	}
+
+	if tx.ID != claimed.ID || !strings.HasPrefix(tx.ID, "at1") {
+		t.Errorf("ParseTransaction() ID = %s, want %s", tx.ID, claimed.ID)
+	}
+	if tx.Type != TransactionTypeExecute {
+		t.Errorf("ParseTransaction() Type = %s, want %s", tx.Type, TransactionTypeExecute)
+	}
+	if tx.Owner != "" || tx.Program != "" {
+		t.Errorf("ParseTransaction() execution has owner %q and program %q", tx.Owner, tx.Program)
+	}
+
+	wantTransition := Transition{
+		ID:       claimed.Execution.Transitions[0].ID,
+		Program:  "credits.aleo",
+		Function: "transfer_public",
+		Inputs: []TransitionIO{
+			{Type: "public", ID: "1field"},
+			{Type: "public", ID: "2field"},
+		},
+		Outputs: []TransitionIO{
+			{Type: "public", ID: "3field"},
+		},
+		TPK: "522678458525321116977504528531602186870683848189190546523208313015552693483group",
+		TCM: "4field",
+		SCM: "5field",
+	}
+	if len(tx.Transitions) != 1 || !reflect.DeepEqual(tx.Transitions[0], wantTransition) {
+		t.Errorf("ParseTransaction() transitions = %+v, want [%+v]", tx.Transitions, wantTransition)
+	}
+
+	if tx.Fee == nil {
+		t.Fatal("ParseTransaction() fee is nil")
+	}
+	if tx.Fee.BaseAmount != 1000 || tx.Fee.PriorityAmount != 25 {
+		t.Errorf("ParseTransaction() fee amounts = %d and %d, want 1000 and 25", tx.Fee.BaseAmount, tx.Fee.PriorityAmount)
+	}
+	wantFee := Transition{
+		ID:       claimed.Fee.Transition.ID,
+		Program:  "credits.aleo",
+		Function: "fee_public",
+		Inputs: []TransitionIO{
+			{Type: "public", ID: "8field", Value: "1000u64"},
+			{Type: "public", ID: "9field", Value: "25u64"},
+			{Type: "public", ID: "10field", Value: "7field"},
+		},
+		Outputs: []TransitionIO{
+			{Type: "public", ID: "11field"},
+		},
+		TPK: "522678458525321116977504528531602186870683848189190546523208313015552693483group",
+		TCM: "12field",
+		SCM: "13field",
+	}
+	if !reflect.DeepEqual(tx.Fee.Transition, wantFee) {
+		t.Errorf("ParseTransaction() fee transition = %+v, want %+v", tx.Fee.Transition, wantFee)
+	}
+
+	if _, err := s.ParseTransaction([]byte(`{"type":"execute","id":"at1"}`)); err == nil {
+		t.Error("ParseTransaction() should fail with malformed JSON transaction")
+	}
+
+	if _, err := s.ParseTransaction([]byte{1, 2, 3}); err == nil {
+		t.Error("ParseTransaction() should fail with malformed binary transaction")
+	}
+
+	if _, err := s.ParseTransaction(nil); err == nil {
+		t.Error("ParseTransaction() should fail with empty transaction")
+	}
+}
+
+func TestDecodeCheckFailures(t *testing.T) {
+	pack := func(fields ...string) []byte {
+		return []byte(packStrings(fields))
+	}
+
+	tests := []struct {
+		name    string
+		buf     []byte
+		want    []TransactionCheckFailure
+		wantErr bool
+	}{
+		{
+			name: "no failures",
+			buf:  pack("at1id"),
+			want: []TransactionCheckFailure{},
+		},
+		{
+			name: "failures",
+			buf:  pack("at1id", TransactionCheckInputID, "au1id", "input ID mismatch at index 1", "", TransactionCheckID, "", "transaction ID mismatch", "at1other"),
+			want: []TransactionCheckFailure{
+				{Check: TransactionCheckInputID, Transition: "au1id", Message: "input ID mismatch at index 1"},
+				{Check: TransactionCheckID, Message: "transaction ID mismatch", Expected: "at1other"},
+			},
+		},
+		{
+			name: "unparsed transaction",
+			buf:  pack("", TransactionCheckFormat, "", "failed to parse transaction", ""),
+			want: []TransactionCheckFailure{
+				{Check: TransactionCheckFormat, Message: "failed to parse transaction"},
+			},
+		},
+		{
+			name:    "empty",
+			buf:     nil,
+			wantErr: true,
+		},
+		{
+			name:    "incomplete failure",
+			buf:     pack("at1id", TransactionCheckInputID, "au1id", "input ID mismatch at index 1"),
+			wantErr: true,
+		},
+		{
+			name:    "malformed",
+			buf:     []byte{1, 2},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCheckFailures(tt.buf)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeCheckFailures() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeCheckFailures() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// checkTransactionFixture is an execution of credits.aleo/transfer_public without proofs and values, so its IDs only
+// depend on the claimed input and output IDs. The claimed transition and transaction IDs are replaced with
+// the recomputed ones by consistentTransaction.
+const checkTransactionFixture = `{
+	"type": "execute",
+	"id": "at1claimed",
+	"execution": {
+		"transitions": [{
+			"id": "au1transfer",
+			"program": "credits.aleo",
+			"function": "transfer_public",
+			"inputs": [
+				{"type": "public", "id": "1field"},
+				{"type": "public", "id": "2field"}
+			],
+			"outputs": [
+				{"type": "public", "id": "3field"}
+			],
+			"tpk": "522678458525321116977504528531602186870683848189190546523208313015552693483group",
+			"tcm": "4field",
+			"scm": "5field"
+		}],
+		"global_state_root": "sr1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq6gk0xu"
+	}
+}`
+
+// checkTransactionFee is a credits.aleo/fee_public transition, which pays for the execution with ID 7field
+const checkTransactionFee = `{
+	"transition": {
+		"id": "au1fee",
+		"program": "credits.aleo",
+		"function": "fee_public",
+		"inputs": [
+			{"type": "public", "id": "8field"},
+			{"type": "public", "id": "9field"},
+			{"type": "public", "id": "10field", "value": "7field"}
+		],
+		"outputs": [
+			{"type": "public", "id": "11field"}
+		],
+		"tpk": "522678458525321116977504528531602186870683848189190546523208313015552693483group",
+		"tcm": "12field",
+		"scm": "13field"
+	},
+	"global_state_root": "sr1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq6gk0xu"
+}`
+
+// consistentTransaction replaces the claimed transition and transaction IDs with the IDs recomputed by
+// CheckTransactionConsistency, and returns the transaction with the remaining failures
+func consistentTransaction(t *testing.T, s Session, transaction string) (string, []TransactionCheckFailure) {
+	t.Helper()
+
+	// the transaction ID depends on the transition IDs, so it's fixed in the second round
+	for round := 0; round < 3; round++ {
+		failures, err := s.CheckTransactionConsistency([]byte(transaction))
+		if err != nil {
+			t.Fatalf("CheckTransactionConsistency() error = %v", err)
+		}
+
+		var rest []TransactionCheckFailure
+		for _, failure := range failures {
+			switch failure.Check {
+			case TransactionCheckTransitionID:
+				transaction = strings.Replace(transaction, strconv.Quote(failure.Transition), strconv.Quote(failure.Expected), 1)
+			case TransactionCheckID:
+				tx := make(map[string]any)
+				if err := json.Unmarshal([]byte(transaction), &tx); err != nil {
+					t.Fatal(err)
+				}
+				tx["id"] = failure.Expected
+
+				buf, err := json.Marshal(tx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				transaction = string(buf)
+			default:
+				rest = append(rest, failure)
+			}
+		}
+
+		if len(rest) == len(failures) {
+			return transaction, rest
+		}
+	}
+
+	t.Fatalf("CheckTransactionConsistency() didn't converge for %s", transaction)
+	return "", nil
+}
+
+func TestAleoWrapper_CheckTransactionConsistency(t *testing.T) {
+	wrapper, closeFn, err := NewWrapper()
+	if err != nil {
+		t.Fatalf("NewWrapper error = %v\n", err)
 	}
+	defer closeFn()
+
+	s, err := wrapper.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if !wrapper.Capabilities().HasFeature(FeatureTransactionChecks) {
+		t.Fatal("embedded module doesn't support transaction checks")
+	}
+
+	valid, failures := consistentTransaction(t, s, checkTransactionFixture)
+	if len(failures) != 0 {
+		t.Fatalf("CheckTransactionConsistency() = %v for a consistent transaction", failures)
+	}
+
+	tx := new(rawTransaction)
+	if err := json.Unmarshal([]byte(valid), tx); err != nil {
+		t.Fatal(err)
+	}
+	transitionID := tx.Execution.Transitions[0].ID
+	if !strings.HasPrefix(tx.ID, "at1") || !strings.HasPrefix(transitionID, "au1") {
+		t.Fatalf("unexpected recomputed IDs %s and %s", tx.ID, transitionID)
+	}
+
+	// the fee pays for another execution. Its paid ID is a public input, so the input ID doesn't match either
+	withFee, feeFailures := consistentTransaction(t, s, strings.Replace(valid, `"execution"`, `"fee": `+checkTransactionFee+`, "execution"`, 1))
+	feeTransition := new(rawTransaction)
+	if err := json.Unmarshal([]byte(withFee), feeTransition); err != nil {
+		t.Fatal(err)
+	}
+	if feeTransition.Fee == nil {
+		t.Fatalf("transaction %s has no fee", withFee)
+	}
+	feeID := feeTransition.Fee.Transition.ID
+
+	tests := []struct {
+		name        string
+		transaction string
+		failures    []TransactionCheckFailure
+		want        []string
+	}{
+		{
+			name:        "format",
+			transaction: valid[:len(valid)/2],
+			want:        []string{TransactionCheckFormat},
+		},
+		{
+			name:        "transaction ID",
+			transaction: strings.Replace(valid, tx.ID, "at1tampered", 1),
+			want:        []string{TransactionCheckID},
+		},
+		{
+			name:        "transition ID",
+			transaction: strings.Replace(valid, transitionID, "au1tampered", 1),
+			want:        []string{TransactionCheckTransitionID},
+		},
+		{
+			name:        "input ID",
+			transaction: strings.Replace(valid, `"id":"1field"`, `"id":"1field","value":"100u64"`, 1),
+			want:        []string{TransactionCheckInputID},
+		},
+		{
+			name:        "output ID",
+			transaction: strings.Replace(valid, `"id":"3field"`, `"id":"3field","value":"100u64"`, 1),
+			want:        []string{TransactionCheckOutputID},
+		},
+		{
+			name:     "fee ID",
+			failures: feeFailures,
+			want:     []string{TransactionCheckInputID, TransactionCheckFeeID},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := tt.failures
+			if tt.transaction != "" {
+				failures, err = s.CheckTransactionConsistency([]byte(tt.transaction))
+				if err != nil {
+					t.Fatalf("CheckTransactionConsistency() error = %v", err)
+				}
+			}
+
+			checks := make([]string, 0, len(failures))
+			for _, failure := range failures {
+				checks = append(checks, failure.Check)
+			}
+			if !reflect.DeepEqual(checks, tt.want) {
+				t.Errorf("CheckTransactionConsistency() = %v, want %v", failures, tt.want)
+			}
+
+			for _, failure := range failures {
+				switch failure.Check {
+				case TransactionCheckID:
+					if failure.Expected != tx.ID {
+						t.Errorf("expected transaction ID = %s, want %s", failure.Expected, tx.ID)
+					}
+				case TransactionCheckTransitionID:
+					if failure.Transition != "au1tampered" || failure.Expected != transitionID {
+						t.Errorf("transition ID failure = %+v, want %s instead of au1tampered", failure, transitionID)
+					}
+				case TransactionCheckInputID, TransactionCheckOutputID:
+					if failure.Transition == "" {
+						t.Errorf("failure %+v doesn't name the transition", failure)
+					}
+				case TransactionCheckFeeID:
+					if failure.Transition != feeID || failure.Expected == "" {
+						t.Errorf("fee failure = %+v, want the fee transition %s and the execution ID", failure, feeID)
+					}
+				}
+			}
+		})
+	}
+
+	if _, err := s.CheckTransactionConsistency(nil); err == nil {
+		t.Error("CheckTransactionConsistency() should fail with empty transaction")
+	}
+}
+
+// poseidonMessage builds a Leo literal from fuzzer input. Kinds 0 to 9 are integer types, 10 is a boolean and 11
+// is a field.
+func poseidonMessage(kind uint8, value []byte) string {
+	switch kind % 12 {
+	case 10:
+		return literal.FormatBool(len(value) > 0 && value[0]&1 == 1)
+	case 11:
+		return new(field.Base).SetWideBytesLE(value).String()
+	default:
+		typ := literal.Type(kind%12 + 1)
+		buf := make([]byte, typ.Size())
+		copy(buf, value)
+
+		i, err := literal.IntegerFromBytesLE(typ, buf)
+		if err != nil {
+			panic(err)
+		}
+
+		return i.String()
+	}
+}
+
+func FuzzPoseidon_HashMessage(f *testing.F) {
+	wrapper, closeFn, err := NewWrapper()
+	if err != nil {
+		f.Fatalf("NewWrapper error = %v\n", err)
+	}
+	defer closeFn()
+
+	s, err := wrapper.NewSession()
+	if err != nil {
+		f.Fatal(err)
+	}
+	defer s.Close()
+
+	// every kind with zero, maximum and random values
+	rng := mathrand.New(mathrand.NewSource(1))
+	for kind := uint8(0); kind < 12; kind++ {
+		random := make([]byte, 32)
+		rng.Read(random)
+
+		f.Add(kind, []byte{})
+		f.Add(kind, bytes.Repeat([]byte{0xff}, 32))
+		f.Add(kind, random)
+	}
+
+	f.Fuzz(func(t *testing.T, kind uint8, value []byte) {
+		message := []byte(poseidonMessage(kind, value))
+		if kind%2 == 1 {
+			// wrap the literal in a struct and an array
+			message = []byte(fmt.Sprintf("{ value: [%s, %s] }", message, message))
+		}
+
+		want, err := s.HashMessage(message)
+		if err != nil {
+			t.Fatalf("HashMessage(%s) error = %v", message, err)
+		}
+
+		got, err := poseidon.HashMessage(message)
+		if err != nil {
+			t.Fatalf("poseidon.HashMessage(%s) error = %v", message, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("poseidon.HashMessage(%s) = %x, HashMessage = %x", message, got, want)
+		}
+	})
+}
+
+func BenchmarkPoseidon_HashMessage(b *testing.B) {
+	s := newBenchmarkSession(b)
+	message, err := s.FormatMessage([]byte("btc/usd = 1.0"), 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("wasm", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := s.HashMessage(message); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("go", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := poseidon.HashMessage(message); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// TestAleoWrapper_FieldCrossCheck checks the field package against the results of the module for random inputs:
+// Poseidon hashes reduced to u128 and their literals, and addresses derived from private keys, which need
+// the base and scalar field arithmetic and the reduction of hashes to scalars.
+func TestAleoWrapper_FieldCrossCheck(t *testing.T) {
+	wrapper, closeFn, err := NewWrapper(WithRandSource(mathrand.New(mathrand.NewSource(49))))
+	if err != nil {
+		t.Fatalf("NewWrapper error = %v\n", err)
+	}
+	defer closeFn()
+
+	s, err := wrapper.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	rng := mathrand.New(mathrand.NewSource(49))
+
+	t.Run("hash", func(t *testing.T) {
+		for i := 0; i < 64; i++ {
+			message := make([]byte, 1+rng.Intn(512))
+			rng.Read(message)
+
+			formattedMessage, err := s.FormatMessage(message, 1+rng.Intn(4))
+			if err != nil {
+				t.Fatalf("FormatMessage error = %v", err)
+			}
+
+			hash, err := s.HashMessage(formattedMessage)
+			if err != nil {
+				t.Fatalf("HashMessage error = %v", err)
+			}
+			hashString, err := s.HashMessageToString(formattedMessage)
+			if err != nil {
+				t.Fatalf("HashMessageToString error = %v", err)
+			}
+
+			// the module reduces the Poseidon8 hash to its low 128 bits
+			got, err := poseidon.HashMessage(formattedMessage)
+			if err != nil {
+				t.Fatalf("poseidon.HashMessage error = %v", err)
+			}
+			if !bytes.Equal(got, hash) {
+				t.Fatalf("poseidon.HashMessage(%s) = %x, HashMessage = %x", formattedMessage, got, hash)
+			}
+
+			// a u128 is a field element with the same value
+			var fromBytes, fromLiteral field.Base
+			if _, err := fromBytes.SetBytesLE(append(slices.Clone(hash), make([]byte, 16)...)); err != nil {
+				t.Fatalf("SetBytesLE(%x) error = %v", hash, err)
+			}
+			if _, err := fromLiteral.SetString(strings.TrimSuffix(hashString, "u128") + "field"); err != nil {
+				t.Fatalf("SetString(%s) error = %v", hashString, err)
+			}
+			if fromBytes.Equal(&fromLiteral) != 1 {
+				t.Fatalf("field from bytes %x = %s, from literal %s = %s", hash, fromBytes.String(), hashString, fromLiteral.String())
+			}
+			if want := strings.TrimSuffix(hashString, "u128") + "field"; fromBytes.String() != want {
+				t.Fatalf("field from bytes %x = %s, want %s", hash, fromBytes.String(), want)
+			}
+		}
+	})
+
+	t.Run("address", func(t *testing.T) {
+		domain := func(name string) field.Base {
+			return *new(field.Base).SetWideBytesLE([]byte(name))
+		}
+		toLiteral := func(v *field.Scalar) literal.Scalar {
+			s, err := literal.ScalarFromBytesLE(v.BytesLE())
+			if err != nil {
+				t.Fatal(err)
+			}
+			return s
+		}
+		toBase := func(g literal.Group) field.Base {
+			var v field.Base
+			if _, err := v.SetBytesLE(g.BytesLE()); err != nil {
+				t.Fatal(err)
+			}
+			return v
+		}
+
+		for i := 0; i < 16; i++ {
+			key, address, err := s.NewPrivateKey()
+			if err != nil {
+				t.Fatalf("NewPrivateKey error = %v", err)
+			}
+
+			buf, err := codec.DecodePrivateKey(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var seed field.Base
+			if _, err := seed.SetBytesLE(buf); err != nil {
+				t.Fatal(err)
+			}
+
+			// address = G * (sk_sig + r_sig + HashToScalarPSD4(pk_sig.x, pr_sig.x)), as in snarkVM
+			skSig := poseidon.HashToScalar2([]field.Base{domain("AleoAccountSignatureSecretKey0"), seed})
+			rSig := poseidon.HashToScalar2([]field.Base{domain("AleoAccountSignatureRandomizer0.0"), seed})
+			pkSig := literal.Generator().Mul(toLiteral(&skSig))
+			prSig := literal.Generator().Mul(toLiteral(&rSig))
+			skPrf := poseidon.HashToScalar4([]field.Base{toBase(pkSig), toBase(prSig)})
+
+			var viewKey field.Scalar
+			viewKey.Add(&skSig, &rSig)
+			viewKey.Add(&viewKey, &skPrf)
+
+			if got := literal.NewAddress(literal.Generator().Mul(toLiteral(&viewKey))).String(); got != address {
+				t.Fatalf("address of %s = %s, NewPrivateKey = %s", key, got, address)
+			}
+		}
+	})
 }